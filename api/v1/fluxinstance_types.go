@@ -21,6 +21,33 @@ const (
 	EnabledValue        = "enabled"
 	DisabledValue       = "disabled"
 	OutdatedReason      = "OutdatedVersion"
+
+	// ReadOnlyReason indicates that the operator runs in read-only
+	// mode and the changes were not applied to the cluster.
+	ReadOnlyReason = "ReadOnlyMode"
+
+	// MissingArchitectureReason indicates that the image digest of a
+	// component doesn't cover all the cluster nodes' architectures.
+	MissingArchitectureReason = "MissingArchitecture"
+
+	// FIPSCompliantCondition indicates whether the
+	// Flux controllers run FIPS-compliant images.
+	FIPSCompliantCondition = "FIPSCompliant"
+	FIPSImagesReason       = "FIPSImages"
+	NonFIPSImagesReason    = "NonFIPSImages"
+
+	// TenantIsolationCondition indicates whether the tenant
+	// service accounts are restricted to their own namespaces.
+	TenantIsolationCondition                = "TenantIsolation"
+	TenantsIsolatedReason                   = "TenantsIsolated"
+	TenantIsolationBrokenReason             = "TenantIsolationBroken"
+	TenantIsolationVerificationFailedReason = "TenantIsolationVerificationFailed"
+
+	// AwaitingApprovalCondition indicates that a new revision
+	// is pending and waits for a manual approval to be applied.
+	AwaitingApprovalCondition = "AwaitingApproval"
+	ApprovalPendingReason     = "ApprovalPending"
+	ApprovedReason            = "Approved"
 )
 
 var (
@@ -31,6 +58,8 @@ var (
 	ReconcileTimeoutAnnotation       = fmt.Sprintf("%s/reconcileTimeout", GroupVersion.Group)
 	PruneAnnotation                  = fmt.Sprintf("%s/prune", GroupVersion.Group)
 	RevisionAnnotation               = fmt.Sprintf("%s/revision", GroupVersion.Group)
+	ApprovedRevisionAnnotation       = fmt.Sprintf("%s/approvedRevision", GroupVersion.Group)
+	ApprovedByAnnotation             = fmt.Sprintf("%s/approvedBy", GroupVersion.Group)
 )
 
 // FluxInstanceSpec defines the desired state of FluxInstance
@@ -68,6 +97,12 @@ type FluxInstanceSpec struct {
 	// +optional
 	Kustomize *Kustomize `json:"kustomize,omitempty"`
 
+	// Profiles is the list of named patch sets from the operator
+	// profiles library to apply to the Flux installation.
+	// The profiles patches are applied before the kustomize patches.
+	// +optional
+	Profiles []string `json:"profiles,omitempty"`
+
 	// Wait instructs the controller to check the health of all the reconciled
 	// resources. Defaults to true.
 	// +kubebuilder:default:=true
@@ -81,12 +116,26 @@ type FluxInstanceSpec struct {
 	// +optional
 	MigrateResources *bool `json:"migrateResources,omitempty"`
 
+	// ApprovalRequired instructs the controller to wait for a manual approval
+	// before applying a new revision. A revision is approved by setting the
+	// 'fluxcd.controlplane.io/approvedRevision' annotation to its value.
+	// +optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
 	// Sync specifies the source for the cluster sync operation.
 	// When set, a Flux source (GitRepository, OCIRepository or Bucket)
 	// and Flux Kustomization are created to sync the cluster state
 	// with the source repository.
 	// +optional
 	Sync *Sync `json:"sync,omitempty"`
+
+	// Syncs specifies additional sources for the cluster sync operation.
+	// For each entry, a Flux source and Flux Kustomization are created
+	// with the given name, which must be unique across the list.
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.name == x.name))",message="Sync names must be unique"
+	// +optional
+	Syncs []SyncSource `json:"syncs,omitempty"`
 }
 
 // Distribution specifies the version and container registry to pull images from.
@@ -100,6 +149,14 @@ type Distribution struct {
 	// +required
 	Registry string `json:"registry"`
 
+	// Mirror is the address of the container registry, including the
+	// repository path prefix, where the distribution images are mirrored
+	// e.g. 'registry.internal/fluxcd'. When set, the component images are
+	// pulled from the mirror while preserving the tags and digests
+	// resolved for the distribution registry.
+	// +optional
+	Mirror string `json:"mirror,omitempty"`
+
 	// ImagePullSecret is the name of the Kubernetes secret
 	// to use for pulling images.
 	// +optional
@@ -111,6 +168,12 @@ type Distribution struct {
 	// +kubebuilder:validation:Pattern="^oci://.*$"
 	// +optional
 	Artifact string `json:"artifact,omitempty"`
+
+	// ArtifactMediaType is the media type of the OCI artifact layer
+	// containing the Kubernetes manifests for the distribution.
+	// Defaults to 'application/vnd.cncf.flux.content.v1.tar+gzip'.
+	// +optional
+	ArtifactMediaType string `json:"artifactMediaType,omitempty"`
 }
 
 // Component is the name of a controller to install.
@@ -154,6 +217,20 @@ type Cluster struct {
 	// +optional
 	TenantDefaultServiceAccount string `json:"tenantDefaultServiceAccount,omitempty"`
 
+	// Tenants is the list of tenant namespaces for which the operator
+	// generates a ResourceQuota and a LimitRange when the multitenant
+	// lockdown is enabled.
+	// +optional
+	Tenants []Tenant `json:"tenants,omitempty"`
+
+	// VerifyTenantIsolation enables the verification of the tenant
+	// service accounts permissions at reconcile time. When enabled,
+	// the operator checks that the tenants can't access resources
+	// outside their namespace and reports the result in the
+	// TenantIsolation condition.
+	// +optional
+	VerifyTenantIsolation bool `json:"verifyTenantIsolation,omitempty"`
+
 	// NetworkPolicy restricts network access to the current namespace.
 	// Defaults to true.
 	// +kubebuilder:default:=true
@@ -168,6 +245,33 @@ type Cluster struct {
 	Type string `json:"type,omitempty"`
 }
 
+// Tenant is the specification of a tenant namespace.
+type Tenant struct {
+	// Namespace is the name of the tenant namespace.
+	// The namespace must exist before the quotas can be applied.
+	// +required
+	Namespace string `json:"namespace"`
+
+	// ServiceAccount is the name of the tenant service account for which
+	// the operator generates a RoleBinding to the built-in 'admin' ClusterRole
+	// scoped to the tenant namespace. The 'admin' role doesn't allow the
+	// service account to modify the tenant ResourceQuota and LimitRange.
+	// When not specified, no RBAC is generated for the tenant.
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// ResourceQuota holds the hard limits of the tenant ResourceQuota.
+	// Defaults to 'requests.cpu: 4', 'requests.memory: 8Gi',
+	// 'limits.cpu: 8', 'limits.memory: 16Gi' and 'pods: 100'.
+	// +optional
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty"`
+
+	// LimitRange holds the default limits of the tenant containers.
+	// Defaults to 'cpu: 500m' and 'memory: 512Mi'.
+	// +optional
+	LimitRange map[string]string `json:"limitRange,omitempty"`
+}
+
 type Sharding struct {
 	// Key is the label key used to shard the resources.
 	// +kubebuilder:default:=sharding.fluxcd.io/key
@@ -245,6 +349,47 @@ type Sync struct {
 	PullSecret string `json:"pullSecret,omitempty"`
 }
 
+// SyncSource specifies a named source for the cluster sync operation.
+type SyncSource struct {
+	// Name is the name of the Flux source and kustomization resources.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Interval is the time between syncs.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +kubebuilder:default:="1m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Kind is the kind of the source.
+	// +kubebuilder:validation:Enum=OCIRepository;GitRepository;Bucket
+	// +required
+	Kind string `json:"kind"`
+
+	// URL is the source URL, can be a Git repository HTTP/S or SSH address,
+	// an OCI repository address or a Bucket endpoint.
+	// +required
+	URL string `json:"url"`
+
+	// Ref is the source reference, can be a Git ref name e.g. 'refs/heads/main',
+	// an OCI tag e.g. 'latest' or a bucket name e.g. 'flux'.
+	// +required
+	Ref string `json:"ref"`
+
+	// Path is the path to the source directory containing
+	// the kustomize overlay or plain Kubernetes manifests.
+	// +required
+	Path string `json:"path"`
+
+	// PullSecret specifies the Kubernetes Secret containing the
+	// authentication credentials for the source.
+	// +optional
+	PullSecret string `json:"pullSecret,omitempty"`
+}
+
 // ResourceInventory contains a list of Kubernetes resource object references
 // that have been applied.
 type ResourceInventory struct {
@@ -280,6 +425,23 @@ type FluxInstanceStatus struct {
 	// +optional
 	LastAppliedRevision string `json:"lastAppliedRevision,omitempty"`
 
+	// LastAppliedVersion is the Flux distribution semver
+	// version that was last reconciled e.g. 'v2.3.0'.
+	// +optional
+	LastAppliedVersion string `json:"lastAppliedVersion,omitempty"`
+
+	// LastAppliedDigest is the digest of the distribution
+	// manifests that were last reconciled e.g. 'sha256:...'.
+	// +optional
+	LastAppliedDigest string `json:"lastAppliedDigest,omitempty"`
+
+	// LastApprovedBy is the approver of the last applied revision,
+	// as set in the 'fluxcd.controlplane.io/approvedBy' annotation.
+	// The value is not verified against the identity of the user
+	// that annotated the object, hence it must not be used for auditing.
+	// +optional
+	LastApprovedBy string `json:"lastApprovedBy,omitempty"`
+
 	// LastArtifactRevision is the digest of the last pulled
 	// distribution artifact.
 	// +optional
@@ -364,6 +526,15 @@ func (in *FluxInstance) IsDisabled() bool {
 	return ok && strings.ToLower(val) == DisabledValue
 }
 
+// IsApproved returns true if the approval is not required or if
+// the object has the approved revision annotation set to the given revision.
+func (in *FluxInstance) IsApproved(revision string) bool {
+	if !in.Spec.ApprovalRequired {
+		return true
+	}
+	return in.GetAnnotations()[ApprovedRevisionAnnotation] == revision
+}
+
 // GetInterval returns the interval at which the object should be reconciled.
 // If no interval is set, the default is 60 minutes.
 func (in *FluxInstance) GetInterval() time.Duration {
@@ -36,6 +36,20 @@ type FluxReportSpec struct {
 	// Source and Kustomization resources.
 	// +optional
 	SyncStatus *FluxSyncStatus `json:"sync,omitempty"`
+
+	// Delta is the list of Flux resources that changed
+	// their readiness since the previous report.
+	// +optional
+	Delta *FluxReportDelta `json:"delta,omitempty"`
+
+	// Since restricts the per-resource metrics to the Flux resources
+	// whose Ready condition transitioned within the given window.
+	// Resources outside the window are still counted in the reconcilers
+	// statistics. This field is set by users and preserved by the operator.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Since *metav1.Duration `json:"since,omitempty"`
 }
 
 // FluxDistributionStatus defines the version information of the Flux instance.
@@ -76,6 +90,16 @@ type FluxComponentStatus struct {
 	// Image is the container image of the Flux component.
 	// +required
 	Image string `json:"image"`
+
+	// Resources is the total number of Flux resources
+	// reconciled by the component.
+	// +optional
+	Resources int `json:"resources,omitempty"`
+
+	// FailingResources is the number of Flux resources reconciled
+	// by the component that are in a failed Ready state.
+	// +optional
+	FailingResources int `json:"failingResources,omitempty"`
 }
 
 // FluxReconcilerStatus defines the observed state of a Flux reconciler.
@@ -139,6 +163,25 @@ type FluxSyncStatus struct {
 	Source string `json:"source,omitempty"`
 }
 
+// FluxReportDelta defines the readiness transitions
+// observed between two consecutive reports.
+type FluxReportDelta struct {
+	// NewlyFailing is the list of resources that transitioned
+	// to a failed Ready state since the previous report.
+	// +optional
+	NewlyFailing []string `json:"newlyFailing,omitempty"`
+
+	// Recovered is the list of resources that are no longer
+	// in a failed Ready state since the previous report.
+	// +optional
+	Recovered []string `json:"recovered,omitempty"`
+
+	// Truncated is set to true if the lists exceed
+	// the maximum number of entries.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
 // FluxReportStatus defines the readiness of a FluxReport.
 type FluxReportStatus struct {
 	meta.ReconcileRequestStatus `json:",inline"`
@@ -146,6 +189,12 @@ type FluxReportStatus struct {
 	// Conditions contains the readiness conditions of the object.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// FailingResources is the sorted list of Flux resources found
+	// in a failed Ready state at the last complete report.
+	// It is used to compute the delta of the next report.
+	// +optional
+	FailingResources []string `json:"failingResources,omitempty"`
 }
 
 // +kubebuilder:storageversion
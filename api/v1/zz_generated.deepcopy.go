@@ -16,6 +16,13 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
@@ -193,7 +200,7 @@ func (in *FluxInstanceSpec) DeepCopyInto(out *FluxInstanceSpec) {
 	if in.Cluster != nil {
 		in, out := &in.Cluster, &out.Cluster
 		*out = new(Cluster)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Sharding != nil {
 		in, out := &in.Sharding, &out.Sharding
@@ -210,6 +217,11 @@ func (in *FluxInstanceSpec) DeepCopyInto(out *FluxInstanceSpec) {
 		*out = new(Kustomize)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Wait != nil {
 		in, out := &in.Wait, &out.Wait
 		*out = new(bool)
@@ -225,6 +237,13 @@ func (in *FluxInstanceSpec) DeepCopyInto(out *FluxInstanceSpec) {
 		*out = new(Sync)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Syncs != nil {
+		in, out := &in.Syncs, &out.Syncs
+		*out = make([]SyncSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluxInstanceSpec.
@@ -328,6 +347,31 @@ func (in *FluxReport) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FluxReportDelta) DeepCopyInto(out *FluxReportDelta) {
+	*out = *in
+	if in.NewlyFailing != nil {
+		in, out := &in.NewlyFailing, &out.NewlyFailing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recovered != nil {
+		in, out := &in.Recovered, &out.Recovered
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluxReportDelta.
+func (in *FluxReportDelta) DeepCopy() *FluxReportDelta {
+	if in == nil {
+		return nil
+	}
+	out := new(FluxReportDelta)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluxReportList) DeepCopyInto(out *FluxReportList) {
 	*out = *in
@@ -379,6 +423,16 @@ func (in *FluxReportSpec) DeepCopyInto(out *FluxReportSpec) {
 		*out = new(FluxSyncStatus)
 		**out = **in
 	}
+	if in.Delta != nil {
+		in, out := &in.Delta, &out.Delta
+		*out = new(FluxReportDelta)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluxReportSpec.
@@ -402,6 +456,11 @@ func (in *FluxReportStatus) DeepCopyInto(out *FluxReportStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FailingResources != nil {
+		in, out := &in.FailingResources, &out.FailingResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluxReportStatus.
@@ -540,3 +599,52 @@ func (in *Sync) DeepCopy() *Sync {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSource) DeepCopyInto(out *SyncSource) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSource.
+func (in *SyncSource) DeepCopy() *SyncSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// redactedValue replaces the values of the sensitive flags and env vars.
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeywords is the list of keywords that mark
+// a flag or an env var as holding sensitive data.
+var sensitiveKeywords = []string{"token", "password", "secret", "key", "credential"}
+
+// effectiveConfig is the operator configuration
+// resolved from the parsed flags and the environment.
+type effectiveConfig struct {
+	Flags map[string]string `json:"flags"`
+	Env   map[string]string `json:"env"`
+}
+
+// newEffectiveConfig returns the effective configuration for the given
+// flag set and env vars, with the sensitive values redacted.
+// The env vars not set in the environment are omitted.
+func newEffectiveConfig(fs *flag.FlagSet, envKeys []string) effectiveConfig {
+	cfg := effectiveConfig{
+		Flags: make(map[string]string),
+		Env:   make(map[string]string),
+	}
+
+	fs.VisitAll(func(f *flag.Flag) {
+		cfg.Flags[f.Name] = redact(f.Name, f.Value.String())
+	})
+
+	for _, key := range envKeys {
+		if value, ok := os.LookupEnv(key); ok {
+			cfg.Env[key] = redact(key, value)
+		}
+	}
+
+	return cfg
+}
+
+// configHandler returns an HTTP handler that serves
+// the effective configuration of the operator as JSON.
+func configHandler(fs *flag.FlagSet, envKeys []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(newEffectiveConfig(fs, envKeys)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// redact returns the redacted value if the name
+// contains one of the sensitive keywords.
+func redact(name, value string) string {
+	name = strings.ToLower(name)
+	for _, keyword := range sensitiveKeywords {
+		if value != "" && strings.Contains(name, keyword) {
+			return redactedValue
+		}
+	}
+	return value
+}
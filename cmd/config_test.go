@@ -0,0 +1,57 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	flag "github.com/spf13/pflag"
+)
+
+func TestConfigHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv("RUNTIME_NAMESPACE", "flux-system")
+	t.Setenv("API_TOKEN", "s3cr3t")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("concurrent", 4, "")
+	fs.Bool("read-only", false, "")
+	fs.String("storage-path", "/data", "")
+	fs.String("registry-token", "", "")
+	err := fs.Parse([]string{"--concurrent=10", "--read-only", "--registry-token=s3cr3t"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	handler := configHandler(fs, []string{"RUNTIME_NAMESPACE", "API_TOKEN", "MISSING_ENV"})
+
+	req := httptest.NewRequest(http.MethodGet, "/config", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var cfg effectiveConfig
+	err = json.Unmarshal(rec.Body.Bytes(), &cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(cfg.Flags).To(Equal(map[string]string{
+		"concurrent":     "10",
+		"read-only":      "true",
+		"storage-path":   "/data",
+		"registry-token": redactedValue,
+	}))
+	g.Expect(cfg.Env).To(Equal(map[string]string{
+		"RUNTIME_NAMESPACE": "flux-system",
+		"API_TOKEN":         redactedValue,
+	}))
+
+	req = httptest.NewRequest(http.MethodPost, "/config", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
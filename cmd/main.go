@@ -5,6 +5,7 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
 	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
@@ -27,6 +28,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/builder"
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/controller"
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/entitlement"
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/reporter"
@@ -54,18 +56,40 @@ func main() {
 		metricsAddr          string
 		healthAddr           string
 		enableLeaderElection bool
+		entitlementDegraded  bool
+		entitlementGrace     time.Duration
+		readOnly             bool
 		logOptions           logger.Options
 		rateLimiterOptions   runtimeCtrl.RateLimiterOptions
 		storagePath          string
+		profilesPath         string
+		reportConcurrency    int
+		reportBatchSize      int64
 	)
 
 	flag.IntVar(&concurrent, "concurrent", 4, "The number of concurrent kustomize reconciles.")
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&healthAddr, "health-addr", ":8081", "The address the health endpoint binds to.")
 	flag.StringVar(&storagePath, "storage-path", "/data", "The local storage path.")
+	flag.StringVar(&profilesPath, "profiles-path", "",
+		"The path to a directory containing the profiles library, one YAML file of Kustomize patches per profile.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&entitlementDegraded, "entitlement-degraded-mode", false,
+		"Start the operator in degraded mode if the entitlement client fails to initialize. "+
+			"In degraded mode, the entitlement registration and verification are skipped.")
+	flag.BoolVar(&readOnly, "read-only", false,
+		"Start the operator in read-only mode. In read-only mode, the FluxInstance is built, "+
+			"the entitlement is verified and the status is reported, but no changes are applied to the cluster.")
+	flag.DurationVar(&entitlementGrace, "entitlement-grace-period", 0,
+		"The duration after the entitlement expiration during which the operator keeps running "+
+			"and emits warning events before enforcing the expiration.")
+
+	flag.IntVar(&reportConcurrency, "report-concurrency", 4,
+		"The maximum number of resource kinds listed in parallel when computing the FluxReport.")
+	flag.Int64Var(&reportBatchSize, "report-batch-size", 500,
+		"The page size used when listing resources for the FluxReport, zero disables pagination.")
 
 	logOptions.BindFlags(flag.CommandLine)
 	rateLimiterOptions.BindFlags(flag.CommandLine)
@@ -80,13 +104,28 @@ func main() {
 		setupLog.Info("RUNTIME_NAMESPACE env var not set, defaulting to " + fluxcdv1.DefaultNamespace)
 	}
 
+	if profilesPath != "" {
+		if err := builder.LoadProfiles(profilesPath); err != nil {
+			setupLog.Error(err, "unable to load profiles", "path", profilesPath)
+			os.Exit(1)
+		}
+	}
+
 	reporter.MustRegisterMetrics()
 
+	// Expose the effective configuration on the metrics endpoint.
+	metricsHandlers := pprof.GetHandlers()
+	metricsHandlers["/config"] = configHandler(flag.CommandLine, []string{
+		"RUNTIME_NAMESPACE",
+		entitlement.MarketplaceTypeEnvKey,
+		"NOTIFICATIONS_DISABLED",
+	})
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress:   metricsAddr,
-			ExtraHandlers: pprof.GetHandlers(),
+			ExtraHandlers: metricsHandlers,
 		},
 		HealthProbeBindAddress:        healthAddr,
 		LeaderElection:                enableLeaderElection,
@@ -125,10 +164,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	entitlementClient, err := entitlement.NewClient()
+	entitlementClient, err := entitlement.NewClientWithRetry(entitlement.DefaultBackoff, entitlement.NewClient,
+		func(attempt int, err error) {
+			setupLog.Error(err, "unable to create entitlement client, retrying", "attempt", attempt)
+		})
 	if err != nil {
-		setupLog.Error(err, "unable to create entitlement client")
-		os.Exit(1)
+		if !entitlementDegraded {
+			setupLog.Error(err, "unable to create entitlement client")
+			os.Exit(1)
+		}
+		setupLog.Error(err, "unable to create entitlement client, starting in degraded mode")
+		entitlementClient = entitlement.NewUnavailableClient(err)
 	}
 
 	if err = (&controller.EntitlementReconciler{
@@ -139,6 +185,8 @@ func main() {
 		EventRecorder:     mgr.GetEventRecorderFor(controllerName),
 		WatchNamespace:    runtimeNamespace,
 		EntitlementClient: entitlementClient,
+		GracePeriod:       entitlementGrace,
+		ReadOnly:          readOnly,
 	}).SetupWithManager(mgr,
 		controller.EntitlementReconcilerOptions{
 			RateLimiter: runtimeCtrl.GetRateLimiter(rateLimiterOptions),
@@ -149,11 +197,13 @@ func main() {
 
 	if err = (&controller.FluxInstanceReconciler{
 		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
 		Scheme:        mgr.GetScheme(),
 		StatusPoller:  polling.NewStatusPoller(mgr.GetClient(), mgr.GetRESTMapper(), polling.Options{}),
 		StoragePath:   storagePath,
 		StatusManager: controllerName,
 		EventRecorder: mgr.GetEventRecorderFor(controllerName),
+		ReadOnly:      readOnly,
 	}).SetupWithManager(mgr,
 		controller.FluxInstanceReconcilerOptions{
 			RateLimiter: runtimeCtrl.GetRateLimiter(rateLimiterOptions),
@@ -175,11 +225,14 @@ func main() {
 	}
 
 	if err = (&controller.FluxReportReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		StatusManager:  controllerName,
-		EventRecorder:  mgr.GetEventRecorderFor(controllerName),
-		WatchNamespace: runtimeNamespace,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		StatusManager:     controllerName,
+		EventRecorder:     mgr.GetEventRecorderFor(controllerName),
+		WatchNamespace:    runtimeNamespace,
+		ReportConcurrency: reportConcurrency,
+		ReportBatchSize:   reportBatchSize,
+		ReadOnly:          readOnly,
 	}).SetupWithManager(mgr,
 		controller.FluxReportReconcilerOptions{
 			RateLimiter: runtimeCtrl.GetRateLimiter(rateLimiterOptions),
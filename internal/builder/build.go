@@ -5,27 +5,71 @@ package builder
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/fluxcd/pkg/kustomize"
 	"github.com/fluxcd/pkg/ssa"
 	ssautil "github.com/fluxcd/pkg/ssa/utils"
 	"github.com/opencontainers/go-digest"
 	cp "github.com/otiai10/copy"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Build copies the source directory to a temporary directory, generates the
 // required manifests and runs kustomize to build the final resources.
 // The function returns a slice of unstructured objects.
 func Build(srcDir, tmpDir string, options Options) (*Result, error) {
+	for _, component := range options.KustomizeComponents {
+		componentFile := filepath.Join(srcDir, "components", component, "kustomization.yaml")
+		if _, err := os.Stat(componentFile); err != nil {
+			return nil, fmt.Errorf("kustomize component %s not found in %s", component, srcDir)
+		}
+	}
+
+	for component, logging := range options.ComponentLogging {
+		if !ContainElementString(options.Components, component) {
+			return nil, fmt.Errorf("log settings for unknown component %s", component)
+		}
+		if logging.Level == "" && logging.Format == "" {
+			return nil, fmt.Errorf("log settings for component %s must specify a level or a format", component)
+		}
+		if logging.Level != "" && !ContainElementString(logLevels, logging.Level) {
+			return nil, fmt.Errorf("log settings for component %s: invalid level %s, must be one of %v",
+				component, logging.Level, logLevels)
+		}
+		if logging.Format != "" && !ContainElementString(logFormats, logging.Format) {
+			return nil, fmt.Errorf("log settings for component %s: invalid format %s, must be one of %v",
+				component, logging.Format, logFormats)
+		}
+	}
+
 	if err := cp.Copy(srcDir, tmpDir); err != nil {
 		return nil, err
 	}
 
+	if options.RegistryMirror != "" {
+		options.ComponentImages = MirrorComponentImages(options.ComponentImages, options.RegistryMirror)
+	}
+
+	if len(options.ComponentDigests) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		images, err := PinComponentImages(ctx, options.ComponentImages,
+			options.ComponentDigests, options.ImagePullKeychain)
+		if err != nil {
+			return nil, err
+		}
+		options.ComponentImages = images
+	}
+
 	if err := generate(tmpDir, options); err != nil {
 		return nil, err
 	}
@@ -85,11 +129,45 @@ func generate(base string, options Options) error {
 	}
 
 	if options.Sync != nil {
+		options.Syncs = append([]Sync{*options.Sync}, options.Syncs...)
+	}
+
+	if len(options.Syncs) > 0 {
+		names := make(map[string]bool, len(options.Syncs))
+		for _, sync := range options.Syncs {
+			if names[sync.Name] {
+				return fmt.Errorf("generate sync failed: duplicate sync name %s", sync.Name)
+			}
+			names[sync.Name] = true
+		}
 		if err := execTemplate(options, syncTmpl, path.Join(base, "sync.yaml")); err != nil {
 			return fmt.Errorf("generate sync failed: %w", err)
 		}
 	}
 
+	if len(options.Tenants) > 0 {
+		tenants := make([]Tenant, len(options.Tenants))
+		for i, tenant := range options.Tenants {
+			defaults := MakeDefaultTenant(tenant.Namespace)
+			if len(tenant.ResourceQuota) == 0 {
+				tenant.ResourceQuota = defaults.ResourceQuota
+			}
+			if len(tenant.LimitRange) == 0 {
+				tenant.LimitRange = defaults.LimitRange
+			}
+			tenants[i] = tenant
+		}
+		options.Tenants = tenants
+	}
+
+	if len(options.ComponentLogging) > 0 {
+		args, err := componentLogArgs(base, options.ComponentLogging)
+		if err != nil {
+			return fmt.Errorf("generate log settings failed: %w", err)
+		}
+		options.ComponentArgs = args
+	}
+
 	if err := execTemplate(options, kustomizationTmpl, path.Join(base, "kustomization.yaml")); err != nil {
 		return fmt.Errorf("generate kustomization failed: %w", err)
 	}
@@ -126,5 +204,122 @@ func generate(base string, options Options) error {
 		}
 	}
 
+	// the tenant objects are added by an overlay of the distribution
+	// to prevent the namespace transformer from overriding their namespace
+	if len(options.Tenants) > 0 {
+		if err := generateTenants(base, options); err != nil {
+			return fmt.Errorf("generate tenants failed: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// generateTenants moves the distribution manifests to a sub-directory
+// and generates a kustomization overlay with the tenant objects.
+// The overlay applies the patches, labels and annotations to both
+// the distribution and the tenant objects.
+func generateTenants(base string, options Options) error {
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return err
+	}
+
+	distDir := path.Join(base, "distribution")
+	if err := os.MkdirAll(distDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := os.Rename(path.Join(base, entry.Name()), path.Join(distDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	if err := execTemplate(options, tenantsTmpl, path.Join(base, "tenants.yaml")); err != nil {
+		return err
+	}
+
+	return execTemplate(options, kustomizationTenantsTmpl, path.Join(base, "kustomization.yaml"))
+}
+
+var (
+	logLevels  = []string{"debug", "info", "error"}
+	logFormats = []string{"json", "console"}
+)
+
+// componentLogArgs computes the log level and format args of the given
+// components by locating the existing flags in the component manifests.
+func componentLogArgs(base string, logging map[string]ComponentLogging) ([]ComponentArgs, error) {
+	components := make([]string, 0, len(logging))
+	for component := range logging {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	result := make([]ComponentArgs, 0, len(components))
+	for _, component := range components {
+		args, err := containerArgs(filepath.Join(base, component+".yaml"), component)
+		if err != nil {
+			return nil, err
+		}
+
+		ca := ComponentArgs{Name: component}
+		if level := logging[component].Level; level != "" {
+			ca.Args = append(ca.Args, ContainerArg{
+				Index: argIndex(args, "--log-level="),
+				Value: "--log-level=" + level,
+			})
+		}
+		if format := logging[component].Format; format != "" {
+			ca.Args = append(ca.Args, ContainerArg{
+				Index: argIndex(args, "--log-encoding="),
+				Value: "--log-encoding=" + format,
+			})
+		}
+		result = append(result, ca)
+	}
+	return result, nil
+}
+
+// containerArgs returns the args of the first container
+// of the named Deployment found in the given manifest.
+func containerArgs(manifest, name string) ([]string, error) {
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		if obj.GetKind() != "Deployment" || obj.GetName() != name {
+			continue
+		}
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		if len(containers) == 0 {
+			return nil, fmt.Errorf("deployment %s has no containers", name)
+		}
+		container, ok := containers[0].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("deployment %s has an invalid container", name)
+		}
+		args, _, _ := unstructured.NestedStringSlice(container, "args")
+		return args, nil
+	}
+
+	return nil, fmt.Errorf("deployment %s not found in %s", name, filepath.Base(manifest))
+}
+
+// argIndex returns the index of the first arg with the given prefix, or -1.
+func argIndex(args []string, prefix string) int {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return i
+		}
+	}
+	return -1
+}
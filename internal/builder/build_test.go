@@ -5,14 +5,19 @@ package builder
 
 import (
 	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/fluxcd/pkg/apis/kustomize"
+	"github.com/google/go-containerregistry/pkg/registry"
 	. "github.com/onsi/gomega"
 	cp "github.com/otiai10/copy"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 )
 
@@ -72,6 +77,129 @@ func TestBuild_Defaults(t *testing.T) {
 	g.Expect(string(genK)).To(Equal(string(goldenK)))
 }
 
+func TestBuild_RegistryMirror(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+	options.RegistryMirror = "registry.internal/fluxcd/"
+
+	srcDir := filepath.Join("testdata", version)
+	goldenFile := filepath.Join("testdata", version+"-golden", "mirror.kustomization.yaml")
+
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImagesWithDigest(filepath.Join("testdata", "flux-images"), options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Objects).NotTo(BeEmpty())
+
+	if shouldGenGolden() {
+		err = cp.Copy(filepath.Join(dstDir, "kustomization.yaml"), goldenFile)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	genK, err := os.ReadFile(filepath.Join(dstDir, "kustomization.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	goldenK, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(string(genK)).To(Equal(string(goldenK)))
+
+	g.Expect(result.ComponentImages).To(HaveLen(len(ci)))
+	for i, img := range result.ComponentImages {
+		g.Expect(img.Repository).To(Equal("registry.internal/fluxcd/" + img.Name))
+		g.Expect(img.Tag).To(Equal(ci[i].Tag))
+		g.Expect(img.Digest).To(Equal(ci[i].Digest))
+	}
+}
+
+func TestBuild_RegistryMirrorFIPS(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+	options.Registry = "ghcr.io/controlplaneio-fluxcd/distroless"
+	options.RegistryMirror = "registry.internal/fluxcd"
+
+	srcDir := filepath.Join("testdata", version)
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImagesWithDigest(filepath.Join("testdata", "flux-images"), options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(result.ComponentImages).To(HaveLen(len(ci)))
+	for i, img := range result.ComponentImages {
+		g.Expect(img.Repository).To(Equal("registry.internal/fluxcd/" + img.Name))
+		g.Expect(img.SourceRepository).To(Equal(ci[i].Repository))
+		g.Expect(IsFIPSImage(img)).To(BeTrue())
+	}
+}
+
+func TestBuild_KustomizeComponents(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+	options.KustomizeComponents = []string{"webhook-receiver"}
+
+	srcDir := filepath.Join("testdata", version)
+	goldenFile := filepath.Join("testdata", version+"-golden", "components.kustomization.yaml")
+
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImages(srcDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Objects).NotTo(BeEmpty())
+
+	if shouldGenGolden() {
+		err = cp.Copy(filepath.Join(dstDir, "kustomization.yaml"), goldenFile)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	genK, err := os.ReadFile(filepath.Join(dstDir, "kustomization.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	goldenK, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(string(genK)).To(Equal(string(goldenK)))
+
+	found := false
+	for _, obj := range result.Objects {
+		if obj.GetKind() == "Service" && obj.GetName() == "webhook-receiver" {
+			found = true
+			svcType, _, err := unstructured.NestedString(obj.Object, "spec", "type")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(svcType).To(Equal("LoadBalancer"))
+		}
+	}
+	g.Expect(found).To(BeTrue())
+
+	options.KustomizeComponents = []string{"webhook-receiver", "missing"}
+	dstDir, err = testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = Build(srcDir, dstDir, options)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("kustomize component missing not found"))
+}
+
 func TestBuild_Patches(t *testing.T) {
 	g := NewWithT(t)
 	const version = "v2.3.0"
@@ -289,6 +417,383 @@ func TestBuild_Sync(t *testing.T) {
 	g.Expect(found).To(BeTrue())
 }
 
+func TestBuild_MultipleSyncs(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+
+	srcDir := filepath.Join("testdata", version)
+	goldenFile := filepath.Join("testdata", version+"-golden", "syncs.yaml")
+
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImages(srcDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	options.Sync = &Sync{
+		Name:     "infra",
+		Interval: "5m",
+		Kind:     "GitRepository",
+		URL:      "https://host/infra.git",
+		Ref:      "refs/heads/main",
+		Path:     "clusters/prod/infra",
+	}
+	options.Syncs = []Sync{
+		{
+			Name:       "apps",
+			Interval:   "10m",
+			Kind:       "OCIRepository",
+			URL:        "oci://host/apps",
+			Ref:        "latest",
+			Path:       "clusters/prod/apps",
+			PullSecret: "registry-auth",
+		},
+	}
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Objects).NotTo(BeEmpty())
+
+	if shouldGenGolden() {
+		err = cp.Copy(filepath.Join(dstDir, "sync.yaml"), goldenFile)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	genSync, err := os.ReadFile(filepath.Join(dstDir, "sync.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	goldenSync, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(string(genSync)).To(Equal(string(goldenSync)))
+
+	syncObjects := map[string]string{}
+	for _, obj := range result.Objects {
+		switch obj.GetKind() {
+		case "GitRepository", "OCIRepository", "Kustomization":
+			syncObjects[obj.GetKind()+"/"+obj.GetName()] = obj.GetNamespace()
+		}
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("kustomize.toolkit.fluxcd.io/ssa", "Ignore"))
+	}
+	g.Expect(syncObjects).To(Equal(map[string]string{
+		"GitRepository/infra": options.Namespace,
+		"Kustomization/infra": options.Namespace,
+		"OCIRepository/apps":  options.Namespace,
+		"Kustomization/apps":  options.Namespace,
+	}))
+}
+
+func TestBuild_MultipleSyncsDuplicateName(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+
+	srcDir := filepath.Join("testdata", version)
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	sync := Sync{
+		Name:     "flux-system",
+		Interval: "5m",
+		Kind:     "GitRepository",
+		URL:      "https://host/repo.git",
+		Ref:      "refs/heads/main",
+		Path:     "clusters/prod",
+	}
+	options.Sync = &sync
+	options.Syncs = []Sync{sync}
+
+	_, err = Build(srcDir, dstDir, options)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("duplicate sync name flux-system"))
+}
+
+func TestBuild_ComponentLogging(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+
+	srcDir := filepath.Join("testdata", version)
+	goldenFile := filepath.Join("testdata", version+"-golden", "logging.kustomization.yaml")
+
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImages(srcDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	options.ComponentLogging = map[string]ComponentLogging{
+		"source-controller":       {Level: "debug"},
+		"kustomize-controller":    {Format: "console"},
+		"notification-controller": {Level: "error", Format: "console"},
+	}
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Objects).NotTo(BeEmpty())
+
+	if shouldGenGolden() {
+		err = cp.Copy(filepath.Join(dstDir, "kustomization.yaml"), goldenFile)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	genK, err := os.ReadFile(filepath.Join(dstDir, "kustomization.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	goldenK, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(string(genK)).To(Equal(string(goldenK)))
+
+	for _, obj := range result.Objects {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+
+		switch obj.GetName() {
+		case "source-controller":
+			g.Expect(args).To(ContainElements("--log-level=debug", "--log-encoding=json"))
+		case "kustomize-controller":
+			g.Expect(args).To(ContainElements("--log-level=info", "--log-encoding=console"))
+		case "notification-controller":
+			g.Expect(args).To(ContainElements("--watch-all-namespaces=true", "--log-level=error", "--log-encoding=console"))
+		default:
+			g.Expect(args).To(ContainElements("--log-level=info", "--log-encoding=json"))
+		}
+	}
+}
+
+func TestBuild_ComponentLoggingUnknownComponent(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+	options.ComponentLogging = map[string]ComponentLogging{
+		"tf-controller": {Level: "debug"},
+	}
+
+	srcDir := filepath.Join("testdata", version)
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = Build(srcDir, dstDir, options)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unknown component tf-controller"))
+}
+
+func TestBuild_ComponentDigests(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	srcDir := filepath.Join("testdata", version)
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImages(srcDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = MirrorComponentImages(ci, host+"/fluxcd")
+
+	digest, err := pushTestImage(host + "/fluxcd/source-controller:v1.3.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentDigests = map[string]string{"source-controller": digest}
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, img := range result.ComponentImages {
+		if img.Name == "source-controller" {
+			g.Expect(img.Digest).To(Equal(digest))
+		} else {
+			g.Expect(img.Digest).To(BeEmpty())
+		}
+	}
+
+	for _, obj := range result.Objects {
+		if obj.GetKind() == "Deployment" && obj.GetName() == "source-controller" {
+			containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			image := containers[0].(map[string]interface{})["image"].(string)
+			g.Expect(image).To(HaveSuffix("@" + digest))
+		}
+	}
+
+	// Verify that a mirror serving a mutated tag is caught.
+	_, err = pushTestImage(host + "/fluxcd/source-controller:v1.3.0")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = Build(srcDir, dstDir, options)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("digest mismatch"))
+}
+
+func TestBuild_ComponentLoggingInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		logging ComponentLogging
+		err     string
+	}{
+		{
+			name:    "empty settings",
+			logging: ComponentLogging{},
+			err:     "must specify a level or a format",
+		},
+		{
+			name:    "invalid level",
+			logging: ComponentLogging{Level: "trace"},
+			err:     "invalid level trace",
+		},
+		{
+			name:    "invalid format",
+			logging: ComponentLogging{Level: "debug", Format: "text"},
+			err:     "invalid format text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			const version = "v2.3.0"
+			options := MakeDefaultOptions()
+			options.Version = version
+			options.ComponentLogging = map[string]ComponentLogging{
+				"source-controller": tt.logging,
+			}
+
+			srcDir := filepath.Join("testdata", version)
+			dstDir, err := testTempDir(t)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			_, err = Build(srcDir, dstDir, options)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tt.err))
+		})
+	}
+}
+
+func TestArgIndex(t *testing.T) {
+	g := NewWithT(t)
+	args := []string{"--watch-all-namespaces", "--log-level=info", "--log-encoding=json"}
+
+	g.Expect(argIndex(args, "--log-level=")).To(Equal(1))
+	g.Expect(argIndex(args, "--log-encoding=")).To(Equal(2))
+	g.Expect(argIndex(args, "--events-addr=")).To(Equal(-1))
+}
+
+func TestBuild_Tenants(t *testing.T) {
+	g := NewWithT(t)
+	const version = "v2.3.0"
+	options := MakeDefaultOptions()
+	options.Version = version
+	options.Patches = GetMultitenantProfile("")
+	options.Patches += `
+- target:
+    kind: ResourceQuota
+    name: flux-tenant
+  patch: |
+    - op: add
+      path: /metadata/labels/toolkit.fluxcd.io~1tenant
+      value: "true"
+`
+
+	srcDir := filepath.Join("testdata", version)
+	goldenFile := filepath.Join("testdata", version+"-golden", "tenants.yaml")
+
+	dstDir, err := testTempDir(t)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ci, err := ExtractComponentImages(srcDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	options.ComponentImages = ci
+
+	options.Tenants = []Tenant{
+		{Namespace: "team1"},
+		{
+			Namespace:      "team2",
+			ServiceAccount: "flux",
+			ResourceQuota: map[string]string{
+				"pods":          "10",
+				"limits.memory": "4Gi",
+			},
+			LimitRange: map[string]string{
+				"memory": "256Mi",
+			},
+		},
+	}
+
+	result, err := Build(srcDir, dstDir, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Objects).NotTo(BeEmpty())
+
+	if shouldGenGolden() {
+		err = cp.Copy(filepath.Join(dstDir, "tenants.yaml"), goldenFile)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	genT, err := os.ReadFile(filepath.Join(dstDir, "tenants.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	goldenT, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(string(genT)).To(Equal(string(goldenT)))
+
+	found := 0
+	for _, obj := range result.Objects {
+		if obj.GetNamespace() == "team1" || obj.GetNamespace() == "team2" {
+			found++
+			g.Expect(obj.GetName()).To(Equal("flux-tenant"))
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/instance", options.Namespace))
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/version", version))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("kustomize.toolkit.fluxcd.io/ssa", "Ignore"))
+			if obj.GetKind() == "ResourceQuota" {
+				g.Expect(obj.GetLabels()).To(HaveKeyWithValue("toolkit.fluxcd.io/tenant", "true"))
+			} else {
+				g.Expect(obj.GetLabels()).NotTo(HaveKey("toolkit.fluxcd.io/tenant"))
+			}
+		}
+		if obj.GetKind() == "Deployment" {
+			g.Expect(obj.GetNamespace()).To(Equal(options.Namespace))
+			g.Expect(obj.GetLabels()).NotTo(HaveKey("toolkit.fluxcd.io/tenant"))
+		}
+		if obj.GetKind() == "Role" {
+			g.Expect(obj.GetNamespace()).NotTo(BeElementOf("team1", "team2"))
+		}
+		if obj.GetKind() == "RoleBinding" && obj.GetName() == "flux-tenant" {
+			g.Expect(obj.GetNamespace()).To(Equal("team2"))
+
+			// Verify that the tenant can't modify the quota objects.
+			roleRef, _, err := unstructured.NestedStringMap(obj.Object, "roleRef")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(roleRef).To(HaveKeyWithValue("kind", "ClusterRole"))
+			g.Expect(roleRef).To(HaveKeyWithValue("name", "admin"))
+
+			subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(subjects).To(HaveLen(1))
+			g.Expect(subjects[0]).To(HaveKeyWithValue("namespace", "team2"))
+		}
+		if obj.GetKind() == "ClusterRole" || obj.GetKind() == "ClusterRoleBinding" {
+			g.Expect(obj.GetName()).NotTo(HavePrefix("flux-tenant"))
+		}
+	}
+	g.Expect(found).To(Equal(5))
+	g.Expect(options.Tenants[0].ResourceQuota).To(BeEmpty())
+}
+
 func TestBuild_InvalidPatches(t *testing.T) {
 	g := NewWithT(t)
 	const version = "v2.3.0"
@@ -5,6 +5,7 @@ package builder
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/fluxcd/pkg/apis/kustomize"
 	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
 	gcname "github.com/google/go-containerregistry/pkg/name"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
@@ -110,3 +113,97 @@ func ExtractComponentImagesWithDigest(srcDir string, opts Options) (images []Com
 	}
 	return images, nil
 }
+
+// MirrorComponentImages returns a copy of the component images with the
+// repository set to the mirror registry, preserving the tags and digests.
+// The original repository is recorded in the image SourceRepository.
+func MirrorComponentImages(images []ComponentImage, mirror string) []ComponentImage {
+	mirror = strings.TrimSuffix(mirror, "/")
+	result := make([]ComponentImage, len(images))
+	for i, img := range images {
+		if img.SourceRepository == "" {
+			img.SourceRepository = img.Repository
+		}
+		img.Repository = fmt.Sprintf("%s/%s", mirror, img.Name)
+		result[i] = img
+	}
+	return result
+}
+
+// PinComponentImages returns a copy of the component images pinned to the
+// expected digests. The tag of each image with an expected digest is resolved
+// in the container registry, and the build fails if the registry or the image
+// reference points to a different digest, e.g. when a mirror serves a mutated tag.
+// If the keychain is nil, the default keychain is used to authenticate.
+func PinComponentImages(ctx context.Context, images []ComponentImage,
+	digests map[string]string, keychain authn.Keychain) ([]ComponentImage, error) {
+	for component := range digests {
+		if !ContainElementString(componentNames(images), component) {
+			return nil, fmt.Errorf("expected digest for unknown component %s", component)
+		}
+	}
+
+	opts := []crane.Option{crane.WithContext(ctx)}
+	if keychain != nil {
+		opts = append(opts, crane.WithAuthFromKeychain(keychain))
+	}
+
+	result := make([]ComponentImage, len(images))
+	for i, img := range images {
+		if expected, ok := digests[img.Name]; ok {
+			if img.Digest != "" && img.Digest != expected {
+				return nil, fmt.Errorf("image %s:%s digest mismatch, expected %s got %s",
+					img.Repository, img.Tag, expected, img.Digest)
+			}
+
+			ref := fmt.Sprintf("%s:%s", img.Repository, img.Tag)
+			resolved, err := crane.Digest(ref, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("resolving digest for image %s failed: %w", ref, err)
+			}
+			if resolved != expected {
+				return nil, fmt.Errorf("image %s digest mismatch, expected %s got %s from registry",
+					ref, expected, resolved)
+			}
+
+			img.Digest = expected
+		}
+		result[i] = img
+	}
+
+	return result, nil
+}
+
+// componentNames returns the component names of the given images.
+func componentNames(images []ComponentImage) []string {
+	names := make([]string, len(images))
+	for i, img := range images {
+		names[i] = img.Name
+	}
+	return names
+}
+
+// fipsRegistries is the list of container registries
+// hosting FIPS-compliant builds of the Flux controllers.
+var fipsRegistries = []string{
+	"ghcr.io/controlplaneio-fluxcd/alpine",
+	"ghcr.io/controlplaneio-fluxcd/distroless",
+	"709825985650.dkr.ecr.us-east-1.amazonaws.com/controlplane/fluxcd",
+}
+
+// IsFIPSImage returns true if the container image is a FIPS-compliant build.
+// An image is considered FIPS-compliant only if it's pulled from one of the
+// enterprise distribution registries, the image tag is not taken into account.
+// For mirrored images, the registry check is performed on the source repository.
+func IsFIPSImage(img ComponentImage) bool {
+	repository := img.Repository
+	if img.SourceRepository != "" {
+		repository = img.SourceRepository
+	}
+	for _, registry := range fipsRegistries {
+		if strings.HasPrefix(repository, registry+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,17 @@
 package builder
 
 import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	. "github.com/onsi/gomega"
 )
 
@@ -88,3 +96,157 @@ func TestBuild_ExtractImagesWithDigest_AWS(t *testing.T) {
 		},
 	))
 }
+
+func TestPinComponentImages(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	images := []ComponentImage{
+		{
+			Name:       "source-controller",
+			Repository: host + "/fluxcd/source-controller",
+			Tag:        "v1.3.0",
+		},
+		{
+			Name:       "kustomize-controller",
+			Repository: host + "/fluxcd/kustomize-controller",
+			Tag:        "v1.3.0",
+		},
+	}
+
+	scDigest, err := pushTestImage(images[0].Repository + ":" + images[0].Tag)
+	g.Expect(err).NotTo(HaveOccurred())
+	kcDigest, err := pushTestImage(images[1].Repository + ":" + images[1].Tag)
+	g.Expect(err).NotTo(HaveOccurred())
+	images[1].Digest = kcDigest
+
+	keychain, err := NewDockerConfigKeychain([]byte(`{"auths":{}}`))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Pin the images to the digests served by the registry.
+	pinned, err := PinComponentImages(ctx, images, map[string]string{
+		"source-controller":    scDigest,
+		"kustomize-controller": kcDigest,
+	}, keychain)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pinned[0].Digest).To(Equal(scDigest))
+	g.Expect(pinned[1].Digest).To(Equal(kcDigest))
+	g.Expect(images[0].Digest).To(BeEmpty())
+
+	// Fail if the image reference points to a different digest.
+	_, err = PinComponentImages(ctx, images, map[string]string{
+		"kustomize-controller": scDigest,
+	}, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("digest mismatch"))
+
+	// Fail if the registry serves a mutated tag.
+	mutated, err := pushTestImage(images[0].Repository + ":" + images[0].Tag)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = PinComponentImages(ctx, images, map[string]string{
+		"source-controller": scDigest,
+	}, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("got " + mutated + " from registry"))
+
+	// Fail if the tag is not found in the registry.
+	_, err = PinComponentImages(ctx, []ComponentImage{
+		{Name: "helm-controller", Repository: host + "/fluxcd/helm-controller", Tag: "v1.0.1"},
+	}, map[string]string{
+		"helm-controller": scDigest,
+	}, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("resolving digest"))
+
+	_, err = PinComponentImages(ctx, images, map[string]string{
+		"helm-controller": scDigest,
+	}, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unknown component helm-controller"))
+}
+
+// pushTestImage pushes a random image to the given
+// reference and returns the digest of the image.
+func pushTestImage(ref string) (string, error) {
+	img, err := random.Image(256, 1)
+	if err != nil {
+		return "", err
+	}
+	if err := crane.Push(img, ref); err != nil {
+		return "", err
+	}
+	d, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+func TestIsFIPSImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    ComponentImage
+		expected bool
+	}{
+		{
+			name: "upstream",
+			image: ComponentImage{
+				Repository: "ghcr.io/fluxcd/source-controller",
+				Tag:        "v1.3.0",
+			},
+			expected: false,
+		},
+		{
+			name: "enterprise distroless",
+			image: ComponentImage{
+				Repository: "ghcr.io/controlplaneio-fluxcd/distroless/source-controller",
+				Tag:        "v1.3.0",
+			},
+			expected: true,
+		},
+		{
+			name: "enterprise aws",
+			image: ComponentImage{
+				Repository: "709825985650.dkr.ecr.us-east-1.amazonaws.com/controlplane/fluxcd/source-controller",
+				Tag:        "v1.3.0",
+			},
+			expected: true,
+		},
+		{
+			name: "enterprise mirrored",
+			image: ComponentImage{
+				Repository:       "registry.internal/fluxcd/source-controller",
+				Tag:              "v1.3.0",
+				SourceRepository: "ghcr.io/controlplaneio-fluxcd/distroless/source-controller",
+			},
+			expected: true,
+		},
+		{
+			name: "fips tag",
+			image: ComponentImage{
+				Repository: "registry.example.com/fluxcd/source-controller",
+				Tag:        "v1.3.0-fips",
+			},
+			expected: false,
+		},
+		{
+			name: "registry prefix lookalike",
+			image: ComponentImage{
+				Repository: "ghcr.io/controlplaneio-fluxcd/distroless-fake/source-controller",
+				Tag:        "v1.3.0",
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsFIPSImage(tt.image)).To(Equal(tt.expected))
+		})
+	}
+}
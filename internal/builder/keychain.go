@@ -0,0 +1,46 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// dockerConfigKeychain resolves the registry credentials
+// from the auths section of a Docker config.
+type dockerConfigKeychain map[string]authn.AuthConfig
+
+// Resolve returns the credentials matching the registry of the resource,
+// or anonymous access if the registry is not found in the config.
+func (k dockerConfigKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	if cfg, ok := k[target.RegistryStr()]; ok {
+		return authn.FromConfig(cfg), nil
+	}
+	return authn.Anonymous, nil
+}
+
+// NewDockerConfigKeychain returns a keychain from the content of a
+// Kubernetes Secret of type kubernetes.io/dockerconfigjson.
+func NewDockerConfigKeychain(data []byte) (authn.Keychain, error) {
+	var config struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing docker config failed: %w", err)
+	}
+
+	keychain := make(dockerConfigKeychain, len(config.Auths))
+	for registry, cfg := range config.Auths {
+		registry = strings.TrimPrefix(registry, "https://")
+		registry = strings.TrimPrefix(registry, "http://")
+		registry, _, _ = strings.Cut(registry, "/")
+		keychain[registry] = cfg
+	}
+
+	return keychain, nil
+}
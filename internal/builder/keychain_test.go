@@ -0,0 +1,49 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewDockerConfigKeychain(t *testing.T) {
+	g := NewWithT(t)
+
+	keychain, err := NewDockerConfigKeychain([]byte(`{
+  "auths": {
+    "https://registry.example.com/v1/": {"username": "flux", "password": "secret"},
+    "ghcr.io": {"auth": "Zmx1eDp0b2tlbg=="}
+  }
+}`))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for ref, expected := range map[string]*authn.AuthConfig{
+		"registry.example.com/fluxcd/source-controller:v1.3.0":              {Username: "flux", Password: "secret"},
+		"ghcr.io/controlplaneio-fluxcd/distroless/source-controller:v1.3.0": {Username: "flux", Password: "token"},
+	} {
+		r, err := name.ParseReference(ref)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		auth, err := keychain.Resolve(r.Context())
+		g.Expect(err).NotTo(HaveOccurred())
+
+		cfg, err := auth.Authorization()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.Username).To(Equal(expected.Username))
+		g.Expect(cfg.Password).To(Equal(expected.Password))
+	}
+
+	r, err := name.ParseReference("docker.io/fluxcd/source-controller:v1.3.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	auth, err := keychain.Resolve(r.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(auth).To(Equal(authn.Anonymous))
+
+	_, err = NewDockerConfigKeychain([]byte("not json"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -3,27 +3,39 @@
 
 package builder
 
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
 // Options defines the builder configuration.
 type Options struct {
 	Version                string
 	Namespace              string
 	Components             []string
 	ComponentImages        []ComponentImage
+	ComponentDigests       map[string]string
 	EventsAddr             string
 	Registry               string
+	RegistryMirror         string
 	ImagePullSecret        string
+	ImagePullKeychain      authn.Keychain
 	WatchAllNamespaces     bool
 	NetworkPolicy          bool
 	LogLevel               string
+	ComponentLogging       map[string]ComponentLogging
+	ComponentArgs          []ComponentArgs
 	NotificationController string
 	ClusterDomain          string
 	TolerationKeys         []string
 	Patches                string
 	ArtifactStorage        *ArtifactStorage
 	Sync                   *Sync
+	Syncs                  []Sync
 	ShardingKey            string
 	Shards                 []string
 	ShardName              string
+	Tenants                []Tenant
+	KustomizeComponents    []string
 }
 
 // MakeDefaultOptions returns the default builder configuration.
@@ -57,6 +69,10 @@ type ComponentImage struct {
 	Repository string
 	Tag        string
 	Digest     string
+
+	// SourceRepository is the repository the image was
+	// copied from when pulled through a registry mirror.
+	SourceRepository string
 }
 
 // ArtifactStorage represents the source-controller PVC.
@@ -65,6 +81,26 @@ type ArtifactStorage struct {
 	Size  string
 }
 
+// ComponentLogging overrides the log level and format of a Flux component.
+type ComponentLogging struct {
+	Level  string
+	Format string
+}
+
+// ComponentArgs holds the container args to set on a Flux component,
+// with their position computed from the component manifest.
+type ComponentArgs struct {
+	Name string
+	Args []ContainerArg
+}
+
+// ContainerArg represents a container arg and its index in the
+// args list. A negative index appends the arg to the list.
+type ContainerArg struct {
+	Index int
+	Value string
+}
+
 type Sync struct {
 	Name       string
 	Kind       string
@@ -74,3 +110,29 @@ type Sync struct {
 	Interval   string
 	PullSecret string
 }
+
+// Tenant represents a tenant namespace with its resource quotas and RBAC.
+type Tenant struct {
+	Namespace      string
+	ServiceAccount string
+	ResourceQuota  map[string]string
+	LimitRange     map[string]string
+}
+
+// MakeDefaultTenant returns a tenant with the default resource quotas.
+func MakeDefaultTenant(namespace string) Tenant {
+	return Tenant{
+		Namespace: namespace,
+		ResourceQuota: map[string]string{
+			"requests.cpu":    "4",
+			"requests.memory": "8Gi",
+			"limits.cpu":      "8",
+			"limits.memory":   "16Gi",
+			"pods":            "100",
+		},
+		LimitRange: map[string]string{
+			"cpu":    "500m",
+			"memory": "512Mi",
+		},
+	}
+}
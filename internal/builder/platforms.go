@@ -0,0 +1,81 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"golang.org/x/exp/slices"
+)
+
+// GetImageArchitectures looks up the component image by digest in the container
+// registry and returns the list of CPU architectures the image was built for.
+// If the digest points to an image index (manifest list), the architectures
+// of all the manifests in the index are returned.
+// If the keychain is nil, the default keychain is used to authenticate.
+func GetImageArchitectures(ctx context.Context, img ComponentImage, keychain authn.Keychain) ([]string, error) {
+	if img.Digest == "" {
+		return nil, fmt.Errorf("digest not specified for image %s:%s", img.Repository, img.Tag)
+	}
+
+	ref := fmt.Sprintf("%s@%s", img.Repository, img.Digest)
+	opts := []crane.Option{crane.WithContext(ctx)}
+	if keychain != nil {
+		opts = append(opts, crane.WithAuthFromKeychain(keychain))
+	}
+	desc, err := crane.Get(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("fetching manifest for image %s failed: %w", ref, err)
+	}
+
+	var archs []string
+	if desc.MediaType.IsIndex() {
+		index, err := desc.ImageIndex()
+		if err != nil {
+			return nil, fmt.Errorf("parsing index for image %s failed: %w", ref, err)
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, fmt.Errorf("parsing index manifest for image %s failed: %w", ref, err)
+		}
+		for _, m := range manifest.Manifests {
+			if m.Platform == nil || m.Platform.Architecture == "unknown" {
+				continue
+			}
+			if !slices.Contains(archs, m.Platform.Architecture) {
+				archs = append(archs, m.Platform.Architecture)
+			}
+		}
+		return archs, nil
+	}
+
+	image, err := desc.Image()
+	if err != nil {
+		return nil, fmt.Errorf("parsing image %s failed: %w", ref, err)
+	}
+	config, err := image.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("parsing config for image %s failed: %w", ref, err)
+	}
+	if config.Architecture != "" {
+		archs = append(archs, config.Architecture)
+	}
+
+	return archs, nil
+}
+
+// MissingArchitectures returns the architectures from the required
+// list that are not present in the image architectures.
+func MissingArchitectures(imageArchs, requiredArchs []string) []string {
+	var missing []string
+	for _, arch := range requiredArchs {
+		if !slices.Contains(imageArchs, arch) && !slices.Contains(missing, arch) {
+			missing = append(missing, arch)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/gomega"
+)
+
+func TestGetImageArchitectures(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+	repository := strings.TrimPrefix(srv.URL, "http://") + "/fluxcd/source-controller"
+
+	// Push a manifest list for amd64 and arm64.
+	var index v1.ImageIndex = empty.Index
+	for _, arch := range []string{"amd64", "arm64"} {
+		img, err := random.Image(1024, 1)
+		g.Expect(err).NotTo(HaveOccurred())
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{
+			Add: img,
+			Descriptor: v1.Descriptor{
+				Platform: &v1.Platform{OS: "linux", Architecture: arch},
+			},
+		})
+	}
+	indexRef, err := name.ParseReference(repository + ":v1.0.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(remote.WriteIndex(indexRef, index)).To(Succeed())
+	indexDigest, err := index.Digest()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	archs, err := GetImageArchitectures(ctx, ComponentImage{
+		Name:       "source-controller",
+		Repository: repository,
+		Tag:        "v1.0.0",
+		Digest:     indexDigest.String(),
+	}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(archs).To(ConsistOf("amd64", "arm64"))
+	g.Expect(MissingArchitectures(archs, []string{"amd64", "arm64"})).To(BeEmpty())
+	g.Expect(MissingArchitectures(archs, []string{"amd64", "s390x"})).To(Equal([]string{"s390x"}))
+
+	// Push a single-arch image.
+	img, err := random.Image(1024, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	img, err = mutate.ConfigFile(img, &v1.ConfigFile{OS: "linux", Architecture: "amd64"})
+	g.Expect(err).NotTo(HaveOccurred())
+	imgRef, err := name.ParseReference(repository + ":v1.0.0-amd64")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(remote.Write(imgRef, img)).To(Succeed())
+	imgDigest, err := img.Digest()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	archs, err = GetImageArchitectures(ctx, ComponentImage{
+		Name:       "source-controller",
+		Repository: repository,
+		Tag:        "v1.0.0-amd64",
+		Digest:     imgDigest.String(),
+	}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(archs).To(Equal([]string{"amd64"}))
+	g.Expect(MissingArchitectures(archs, []string{"amd64", "arm64"})).To(Equal([]string{"arm64"}))
+
+	// Fail for images without digest.
+	_, err = GetImageArchitectures(ctx, ComponentImage{
+		Name:       "source-controller",
+		Repository: repository,
+		Tag:        "v1.0.0",
+	}, nil)
+	g.Expect(err).To(HaveOccurred())
+}
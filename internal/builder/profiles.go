@@ -3,7 +3,17 @@
 
 package builder
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fluxcd/pkg/apis/kustomize"
+	"sigs.k8s.io/yaml"
+)
 
 const ProfileOpenShift = `
 - target:
@@ -108,3 +118,82 @@ func GetNotificationPatch(namespace string) string {
 
 	return fmt.Sprintf(tmpNotificationPatch, namespace)
 }
+
+// profilesLibrary holds the named patch sets that
+// can be referenced by name in the FluxInstance spec.
+var profilesLibrary = struct {
+	sync.RWMutex
+	profiles map[string]string
+}{profiles: map[string]string{}}
+
+// RegisterProfile adds the patch set to the profiles library under the
+// given name, replacing any existing profile with the same name.
+// The patches must be a YAML list of Kustomize patches.
+func RegisterProfile(name, patches string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	var list []kustomize.Patch
+	if err := yaml.Unmarshal([]byte(patches), &list); err != nil {
+		return fmt.Errorf("invalid patches in profile %s: %w", name, err)
+	}
+
+	if !strings.HasSuffix(patches, "\n") {
+		patches += "\n"
+	}
+
+	profilesLibrary.Lock()
+	defer profilesLibrary.Unlock()
+	profilesLibrary.profiles[name] = patches
+	return nil
+}
+
+// LoadProfiles registers a profile for each YAML file found in the
+// given directory, using the file name without extension as the
+// profile name.
+func LoadProfiles(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading profile failed: %w", err)
+		}
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if err := RegisterProfile(name, string(data)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetProfiles returns the concatenated patches of the named profiles
+// in the given order. If any of the names is not found in the profiles
+// library, an error listing the unknown profiles is returned.
+func GetProfiles(names []string) (string, error) {
+	profilesLibrary.RLock()
+	defer profilesLibrary.RUnlock()
+
+	var patches strings.Builder
+	var unknown []string
+	for _, name := range names {
+		profile, ok := profilesLibrary.profiles[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		patches.WriteString(profile)
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("unknown profiles: %s", strings.Join(unknown, ", "))
+	}
+
+	return patches.String(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetProfiles(t *testing.T) {
+	g := NewWithT(t)
+
+	err := RegisterProfile("test-replicas", `
+- target:
+    kind: Deployment
+  patch: |
+    - op: replace
+      path: /spec/replicas
+      value: 0`)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = RegisterProfile("test-openshift", ProfileOpenShift)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	patches, err := GetProfiles([]string{"test-replicas", "test-openshift"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patches).To(ContainSubstring("/spec/replicas"))
+	g.Expect(patches).To(HaveSuffix(ProfileOpenShift))
+
+	_, err = GetProfiles([]string{"test-replicas", "unknown2", "unknown1"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("unknown profiles: unknown1, unknown2"))
+
+	err = RegisterProfile("test-invalid", "target: Deployment")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("test-invalid"))
+}
+
+func TestLoadProfiles(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "test-org.yaml"), []byte(`
+- target:
+    kind: Namespace
+  patch: |
+    - op: add
+      path: /metadata/labels/org
+      value: test
+`), 0o644)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	err = LoadProfiles(dir)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	patches, err := GetProfiles([]string{"test-org"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patches).To(ContainSubstring("/metadata/labels/org"))
+}
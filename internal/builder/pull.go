@@ -10,12 +10,22 @@ import (
 
 	"github.com/fluxcd/pkg/tar"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 )
 
+// DefaultArtifactMediaType is the media type of the layer
+// containing the manifests in the artifacts pushed with Flux CLI.
+const DefaultArtifactMediaType = "application/vnd.cncf.flux.content.v1.tar+gzip"
+
 // PullArtifact downloads an artifact from an OCI repository and extracts the content
-// of the first tgz layer to the given destination directory.
+// of the first tgz layer matching the media type to the given destination directory.
+// If the media type is empty, the DefaultArtifactMediaType is used.
 // It returns the digest of the artifact.
-func PullArtifact(ctx context.Context, ociURL, dstDir string) (string, error) {
+func PullArtifact(ctx context.Context, ociURL, mediaType, dstDir string) (string, error) {
+	if mediaType == "" {
+		mediaType = DefaultArtifactMediaType
+	}
+
 	img, err := crane.Pull(strings.TrimPrefix(ociURL, "oci://"), crane.WithContext(ctx))
 	if err != nil {
 		return "", fmt.Errorf("pulling artifact %s failed: %w", ociURL, err)
@@ -35,7 +45,23 @@ func PullArtifact(ctx context.Context, ociURL, dstDir string) (string, error) {
 		return "", fmt.Errorf("no layers found in artifact %s", ociURL)
 	}
 
-	blob, err := layers[0].Compressed()
+	layerIndex := -1
+	for i, layer := range layers {
+		mt, err := layer.MediaType()
+		if err != nil {
+			return "", fmt.Errorf("reading layer media type in artifact %s failed: %w", ociURL, err)
+		}
+		if mt == types.MediaType(mediaType) {
+			layerIndex = i
+			break
+		}
+	}
+
+	if layerIndex < 0 {
+		return "", fmt.Errorf("no layer with media type %s found in artifact %s", mediaType, ociURL)
+	}
+
+	blob, err := layers[layerIndex].Compressed()
 	if err != nil {
 		return "", fmt.Errorf("extracting layer from artifact %s failed: %w", ociURL, err)
 	}
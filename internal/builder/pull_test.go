@@ -0,0 +1,118 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestPullArtifact(t *testing.T) {
+	const customMediaType = "application/vnd.example.manifests.v1.tar+gzip"
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	tests := []struct {
+		name      string
+		layerType string
+		mediaType string
+		expectErr string
+	}{
+		{
+			name:      "default media type",
+			layerType: DefaultArtifactMediaType,
+			mediaType: "",
+		},
+		{
+			name:      "custom media type",
+			layerType: customMediaType,
+			mediaType: customMediaType,
+		},
+		{
+			name:      "media type mismatch",
+			layerType: customMediaType,
+			mediaType: "",
+			expectErr: "no layer with media type " + DefaultArtifactMediaType,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			ociURL := fmt.Sprintf("oci://%s/manifests:v%d", host, i)
+
+			err := pushTestArtifact(ociURL, tt.layerType, map[string]string{
+				"flux/v2.3.0/kustomization.yaml": "resources: []",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			dstDir, err := testTempDir(t)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			digest, err := PullArtifact(ctx, ociURL, tt.mediaType, dstDir)
+			if tt.expectErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(digest).To(HavePrefix("sha256:"))
+			g.Expect(filepath.Join(dstDir, "flux", "v2.3.0", "kustomization.yaml")).To(BeARegularFile())
+		})
+	}
+}
+
+func pushTestArtifact(ociURL, mediaType string, files map[string]string) error {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(buf.Bytes(), types.MediaType(mediaType)))
+	if err != nil {
+		return err
+	}
+
+	return crane.Push(img, strings.TrimPrefix(ociURL, "oci://"))
+}
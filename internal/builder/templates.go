@@ -18,7 +18,6 @@ var kustomizationTmpl = `---
 {{- $logLevel := .LogLevel }}
 {{- $clusterDomain := .ClusterDomain }}
 {{- $artifactStorage := .ArtifactStorage }}
-{{- $sync := .Sync }}
 {{- $namespace := .Namespace }}
 apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
@@ -41,9 +40,15 @@ resources:
 {{- if $artifactStorage }}
   - pvc.yaml
 {{- end }}
-{{- if $sync }}
+{{- if .Syncs }}
   - sync.yaml
 {{- end }}
+{{- if .KustomizeComponents }}
+components:
+{{- range .KustomizeComponents }}
+  - components/{{.}}
+{{- end }}
+{{- end }}
 {{- if $registry }}
 images:
 {{- range .ComponentImages }}
@@ -130,6 +135,25 @@ patches:
       value: --log-level={{$logLevel}}
 {{- end }}
 {{- end }}
+{{- range .ComponentArgs }}
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: {{.Name}}
+  patch: |-
+{{- range .Args }}
+{{- if lt .Index 0 }}
+    - op: add
+      path: /spec/template/spec/containers/0/args/-
+      value: {{.Value}}
+{{- else }}
+    - op: replace
+      path: /spec/template/spec/containers/0/args/{{.Index}}
+      value: {{.Value}}
+{{- end }}
+{{- end }}
+{{- end }}
 {{- if gt (len .Shards) 0 }}
 - target:
     kind: Deployment
@@ -140,7 +164,24 @@ patches:
       path: /spec/template/spec/containers/0/args/-
       value: --watch-label-selector=!{{.ShardingKey}}
 {{- end }}
+{{- if not .Tenants }}
 {{ .Patches }}
+{{- end }}
+`
+
+var kustomizationTenantsTmpl = `---
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+transformers:
+  - distribution/annotations.yaml
+  - distribution/labels.yaml
+resources:
+  - distribution
+  - tenants.yaml
+{{- if .Patches }}
+patches:
+{{ .Patches }}
+{{- end }}
 `
 
 var kustomizationShardTmpl = `---
@@ -303,52 +344,97 @@ spec:
       storage: {{.ArtifactStorage.Size}}
 `
 
-var syncTmpl = `---
-{{- $sync := .Sync }}
-{{- $name := .Sync.Name }}
-{{- $namespace := .Namespace }}
-{{- if eq $sync.Kind "GitRepository" }}
+var syncTmpl = `{{- $namespace := .Namespace }}
+{{- range .Syncs }}
+---
+{{- if eq .Kind "GitRepository" }}
 apiVersion: source.toolkit.fluxcd.io/v1
 kind: GitRepository
-{{- else if eq $sync.Kind "OCIRepository" }}
+{{- else if eq .Kind "OCIRepository" }}
 apiVersion: source.toolkit.fluxcd.io/v1beta2
 kind: OCIRepository
-{{- else if eq $sync.Kind "Bucket" }}
+{{- else if eq .Kind "Bucket" }}
 apiVersion: source.toolkit.fluxcd.io/v1beta2
 kind: Bucket
 {{- end }}
 metadata:
-  name: {{$name}}
+  name: {{.Name}}
   namespace: {{$namespace}}
 spec:
-  interval: {{$sync.Interval}}
-{{- if eq $sync.Kind "GitRepository" }}
+  interval: {{.Interval}}
+{{- if eq .Kind "GitRepository" }}
   ref:
-    name: {{$sync.Ref}}
-{{- else if eq $sync.Kind "OCIRepository" }}
+    name: {{.Ref}}
+{{- else if eq .Kind "OCIRepository" }}
   ref:
-    tag: {{$sync.Ref}}
-{{- else if eq $sync.Kind "Bucket" }}
-  bucketName: {{$sync.Ref}}
+    tag: {{.Ref}}
+{{- else if eq .Kind "Bucket" }}
+  bucketName: {{.Ref}}
 {{- end }}
-{{- if $sync.PullSecret }}
+{{- if .PullSecret }}
   secretRef:
-    name: {{$sync.PullSecret}}
+    name: {{.PullSecret}}
 {{- end }}
-  url: {{$sync.URL}}
+  url: {{.URL}}
 ---
 apiVersion: kustomize.toolkit.fluxcd.io/v1
 kind: Kustomization
 metadata:
-  name: {{$name}}
+  name: {{.Name}}
   namespace: {{$namespace}}
 spec:
   interval: 10m0s
-  path: {{$sync.Path}}
+  path: {{.Path}}
   prune: true
   sourceRef:
-    kind: {{$sync.Kind}}
-    name: {{$name}}
+    kind: {{.Kind}}
+    name: {{.Name}}
+{{- end }}
+`
+
+var tenantsTmpl = `
+{{- range .Tenants }}
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: flux-tenant
+  namespace: {{.Namespace}}
+spec:
+  hard:
+{{- range $key, $value := .ResourceQuota }}
+    {{$key}}: "{{$value}}"
+{{- end }}
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: flux-tenant
+  namespace: {{.Namespace}}
+spec:
+  limits:
+    - type: Container
+      default:
+{{- range $key, $value := .LimitRange }}
+        {{$key}}: "{{$value}}"
+{{- end }}
+{{- if .ServiceAccount }}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: flux-tenant
+  namespace: {{.Namespace}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: admin
+subjects:
+  - kind: ServiceAccount
+    name: {{.ServiceAccount}}
+    namespace: {{.Namespace}}
+{{- end }}
+{{- end }}
 `
 
 func execTemplate(obj interface{}, tmpl, filename string) (err error) {
@@ -5,6 +5,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kuberecorder "k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -33,6 +35,8 @@ type EntitlementReconciler struct {
 	kuberecorder.EventRecorder
 
 	EntitlementClient entitlement.Client
+	GracePeriod       time.Duration
+	ReadOnly          bool
 	Scheme            *runtime.Scheme
 	StatusPoller      *polling.StatusPoller
 	StatusManager     string
@@ -49,6 +53,10 @@ func (r *EntitlementReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	if r.ReadOnly {
+		return r.reconcileReadOnly(ctx, namespace)
+	}
+
 	secret, err := r.GetEntitlementSecret(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -67,8 +75,16 @@ func (r *EntitlementReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Register the usage if the token is missing and update the secret.
 	if token == "" {
-		token, err = r.EntitlementClient.RegisterUsage(ctx, id)
+		token, err = entitlement.RegisterUsageWithRetry(ctx, entitlement.DefaultBackoff, r.EntitlementClient, id)
 		if err != nil {
+			if errors.Is(err, entitlement.ErrUnavailable) {
+				log.Error(err, "Entitlement registration skipped in degraded mode",
+					"vendor", r.EntitlementClient.GetVendor())
+				if err := r.markUnavailable(ctx, secret); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+			}
 			return ctrl.Result{}, fmt.Errorf("failed to register usage for vendor %s: %w",
 				r.EntitlementClient.GetVendor(), err)
 		}
@@ -91,6 +107,29 @@ func (r *EntitlementReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Verify the token and delete the secret if it is invalid.
 	valid, err := r.EntitlementClient.Verify(token, id)
+	if errors.Is(err, entitlement.ErrUnavailable) {
+		log.Error(err, "Entitlement verification skipped in degraded mode",
+			"vendor", r.EntitlementClient.GetVendor())
+		if err := r.markUnavailable(ctx, secret); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+	}
+
+	// Keep the entitlement active if the token expired within the grace period.
+	var expErr *entitlement.ExpiredError
+	if errors.As(err, &expErr) && expErr.InGracePeriod(r.GracePeriod, time.Now()) {
+		graceEnd := expErr.ExpiresAt.Add(r.GracePeriod)
+		msg := fmt.Sprintf("Entitlement expired, enforcement starts at %s",
+			graceEnd.UTC().Format(time.RFC3339))
+		log.Error(err, msg, "vendor", r.EntitlementClient.GetVendor())
+		r.Event(namespace, corev1.EventTypeWarning, entitlement.GracePeriodReason, msg)
+		if err := r.annotate(ctx, secret, entitlement.GracePeriodAnnotation, graceEnd.UTC().Format(time.RFC3339)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: min(30*time.Minute, time.Until(graceEnd))}, nil
+	}
+
 	if !valid {
 		if err := r.DeleteEntitlementSecret(ctx, secret); err != nil {
 			return ctrl.Result{}, err
@@ -98,6 +137,52 @@ func (r *EntitlementReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("failed to verify entitlement: %w", err)
 	}
 
+	// Clear the grace period once the entitlement is renewed
+	// and the unknown status once the service is reachable.
+	if err := r.annotate(ctx, secret, entitlement.GracePeriodAnnotation, ""); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.annotate(ctx, secret, entitlement.StatusAnnotation, ""); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Entitlement verified", "vendor", r.EntitlementClient.GetVendor())
+	return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+}
+
+// reconcileReadOnly verifies the entitlement token without initializing,
+// updating or deleting the entitlement secret.
+func (r *EntitlementReconciler) reconcileReadOnly(ctx context.Context, namespace *corev1.Namespace) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	secretName := fmt.Sprintf("%s-entitlement", r.StatusManager)
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: r.WatchNamespace,
+		Name:      secretName,
+	}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info(fmt.Sprintf("Entitlement %s/%s not found, initialization skipped in read-only mode",
+				r.WatchNamespace, secretName))
+			return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get %s: %w", secretName, err)
+	}
+
+	token, found := secret.Data[entitlement.TokenKey]
+	if !found || len(token) == 0 {
+		log.Info("Entitlement registration skipped in read-only mode",
+			"vendor", r.EntitlementClient.GetVendor())
+		return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+	}
+
+	valid, err := r.EntitlementClient.Verify(string(token), string(namespace.UID))
+	if !valid {
+		log.Error(err, "Entitlement verification failed, enforcement skipped in read-only mode",
+			"vendor", r.EntitlementClient.GetVendor())
+		return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
+	}
+
 	log.Info("Entitlement verified", "vendor", r.EntitlementClient.GetVendor())
 	return ctrl.Result{RequeueAfter: 30 * time.Minute}, nil
 }
@@ -110,11 +195,25 @@ type EntitlementReconcilerOptions struct {
 // SetupWithManager sets up the controller with the Manager and initializes the
 // entitlement secret in the watch namespace.
 func (r *EntitlementReconciler) SetupWithManager(mgr ctrl.Manager, opts EntitlementReconcilerOptions) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	if _, err := r.InitEntitlementSecret(ctx); err != nil {
-		return err
+	// Skip the initialization in read-only mode to avoid mutating the cluster.
+	if !r.ReadOnly {
+		attempt := 0
+		err := retry.OnError(entitlement.DefaultBackoff, entitlement.IsTransientError, func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			attempt++
+			if _, err := r.InitEntitlementSecret(ctx); err != nil {
+				if entitlement.IsTransientError(err) {
+					mgr.GetLogger().Error(err, "unable to initialize entitlement, retrying", "attempt", attempt)
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	ps, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
@@ -217,6 +316,51 @@ func (r *EntitlementReconciler) UpdateEntitlementSecret(ctx context.Context, tok
 	return nil
 }
 
+// markUnavailable sets the unknown status on the entitlement secret
+// and requests a report update for the status to be surfaced.
+func (r *EntitlementReconciler) markUnavailable(ctx context.Context, secret *corev1.Secret) error {
+	if secret.GetAnnotations()[entitlement.StatusAnnotation] == entitlement.UnavailableStatus {
+		return nil
+	}
+
+	if err := r.annotate(ctx, secret, entitlement.StatusAnnotation, entitlement.UnavailableStatus); err != nil {
+		return err
+	}
+
+	if err := reporter.RequestReportUpdate(ctx,
+		r.Client, fluxcdv1.DefaultInstanceName,
+		r.StatusManager, r.WatchNamespace); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to request report update")
+	}
+
+	return nil
+}
+
+// annotate sets the annotation on the entitlement secret,
+// or removes the annotation if the value is empty.
+func (r *EntitlementReconciler) annotate(ctx context.Context, secret *corev1.Secret, key, value string) error {
+	annotations := secret.GetAnnotations()
+	if annotations[key] == value {
+		return nil
+	}
+
+	if value == "" {
+		delete(annotations, key)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[key] = value
+	}
+	secret.SetAnnotations(annotations)
+
+	if err := r.Client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update %s: %w", secret.Name, err)
+	}
+
+	return nil
+}
+
 // DeleteEntitlementSecret deletes the entitlement secret.
 func (r *EntitlementReconciler) DeleteEntitlementSecret(ctx context.Context, secret *corev1.Secret) error {
 	if err := r.Client.Delete(ctx, secret); err != nil {
@@ -5,12 +5,15 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -66,6 +69,168 @@ func TestEntitlementReconciler_InitEntitlementSecret(t *testing.T) {
 	g.Expect(secret.Data).To(HaveKeyWithValue(entitlement.VendorKey, []byte(entitlement.DefaultVendor)))
 }
 
+func TestEntitlementReconciler_ReconcileDegradedMode(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler := getEntitlementReconciler(ns.Name)
+	reconciler.EntitlementClient = entitlement.NewUnavailableClient(errors.New("service unreachable"))
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err := reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue(entitlement.VendorKey, []byte(entitlement.DefaultVendor)))
+	g.Expect(secret.Data).ToNot(HaveKey(entitlement.TokenKey))
+	g.Expect(secret.Annotations).To(HaveKeyWithValue(entitlement.StatusAnnotation, entitlement.UnavailableStatus))
+
+	// Verify that an existing token is preserved in degraded mode.
+	err = reconciler.UpdateEntitlementSecret(ctx, "token")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err = reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue(entitlement.TokenKey, []byte("token")))
+	g.Expect(secret.Annotations).To(HaveKeyWithValue(entitlement.StatusAnnotation, entitlement.UnavailableStatus))
+
+	// Verify that the unknown status is cleared once the service is reachable.
+	reconciler.EntitlementClient = &renewedClient{}
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err = reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Annotations).ToNot(HaveKey(entitlement.StatusAnnotation))
+}
+
+func TestEntitlementReconciler_ReconcileGracePeriod(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler := getEntitlementReconciler(ns.Name)
+	reconciler.EntitlementClient = &expiredClient{expiresAt: time.Now().Add(-time.Hour)}
+	reconciler.GracePeriod = 2 * time.Hour
+
+	err = reconciler.UpdateEntitlementSecret(ctx, "token")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Verify that the token is preserved during the grace period.
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err := reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue(entitlement.TokenKey, []byte("token")))
+	g.Expect(secret.Annotations).To(HaveKeyWithValue(entitlement.GracePeriodAnnotation,
+		reconciler.EntitlementClient.(*expiredClient).expiresAt.Add(2*time.Hour).UTC().Format(time.RFC3339)))
+
+	// Verify that the grace period is cleared after renewal.
+	reconciler.EntitlementClient = &renewedClient{}
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err = reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Annotations).ToNot(HaveKey(entitlement.GracePeriodAnnotation))
+
+	// Verify that the expiration is enforced after the grace period.
+	reconciler.EntitlementClient = &expiredClient{expiresAt: time.Now().Add(-time.Hour)}
+	reconciler.GracePeriod = 30 * time.Minute
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("entitlement expired"))
+
+	secret, err = reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Data).ToNot(HaveKey(entitlement.TokenKey))
+}
+
+func TestEntitlementReconciler_ReconcileReadOnly(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler := getEntitlementReconciler(ns.Name)
+	reconciler.ReadOnly = true
+
+	// Verify that the secret is not initialized in read-only mode.
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret := &corev1.Secret{}
+	err = testClient.Get(ctx, client.ObjectKey{
+		Namespace: ns.Name,
+		Name:      fmt.Sprintf("%s-entitlement", controllerName),
+	}, secret)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Verify that an invalid token is not deleted in read-only mode.
+	err = reconciler.UpdateEntitlementSecret(ctx, "token")
+	g.Expect(err).ToNot(HaveOccurred())
+	reconciler.EntitlementClient = &expiredClient{expiresAt: time.Now().Add(-time.Hour)}
+
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ns)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+
+	secret, err = reconciler.GetEntitlementSecret(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue(entitlement.TokenKey, []byte("token")))
+	g.Expect(secret.Annotations).ToNot(HaveKey(entitlement.GracePeriodAnnotation))
+}
+
+// expiredClient is an entitlement client that
+// verifies tokens as authentic but expired.
+type expiredClient struct {
+	expiresAt time.Time
+}
+
+func (c *expiredClient) RegisterUsage(_ context.Context, _ string) (string, error) {
+	return "token", nil
+}
+
+func (c *expiredClient) Verify(_, _ string) (bool, error) {
+	return false, &entitlement.ExpiredError{ExpiresAt: c.expiresAt}
+}
+
+func (c *expiredClient) GetVendor() string {
+	return entitlement.DefaultVendor
+}
+
+// renewedClient is an entitlement client that
+// verifies all tokens as valid.
+type renewedClient struct {
+	expiredClient
+}
+
+func (c *renewedClient) Verify(_, _ string) (bool, error) {
+	return true, nil
+}
+
 func getEntitlementReconciler(ns string) *EntitlementReconciler {
 	ec, err := entitlement.NewClient()
 	if err != nil {
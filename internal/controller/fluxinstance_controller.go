@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
@@ -20,6 +21,8 @@ import (
 	"github.com/fluxcd/pkg/ssa"
 	"github.com/fluxcd/pkg/ssa/normalize"
 	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,6 +50,19 @@ type FluxInstanceReconciler struct {
 	StatusPoller  *polling.StatusPoller
 	StatusManager string
 	StoragePath   string
+	ReadOnly      bool
+
+	// APIReader is used to list nodes and read the image pull secret
+	// without starting informers. When not set, the client is used.
+	APIReader client.Reader
+
+	// imageArchs caches the architectures of the component images
+	// keyed by image digest reference.
+	imageArchs sync.Map
+
+	// tenantIsolation records the revision and the tenant set
+	// of the last isolation verification keyed by instance.
+	tenantIsolation sync.Map
 }
 
 // +kubebuilder:rbac:groups=fluxcd.controlplane.io,resources=fluxinstances,verbs=get;list;watch;create;update;patch;delete
@@ -86,6 +102,12 @@ func (r *FluxInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Uninstall if the object is under deletion.
 	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		if r.ReadOnly {
+			msg := "Uninstall skipped in read-only mode"
+			log.Info(msg)
+			r.Event(obj, corev1.EventTypeWarning, fluxcdv1.ReadOnlyReason, msg)
+			return ctrl.Result{}, nil
+		}
 		return r.uninstall(ctx, obj)
 	}
 
@@ -184,11 +206,64 @@ func (r *FluxInstanceReconciler) reconcile(ctx context.Context,
 		log.Info(msg)
 		r.EventRecorder.Event(obj, corev1.EventTypeNormal, meta.ProgressingReason, msg)
 		obj.Status.LastAttemptedRevision = buildResult.Revision
+
+		// Warn if the image digests don't cover the cluster architectures.
+		r.verifyImageArchitectures(ctx, obj, buildResult.ComponentImages)
 	}
 	if err := r.patch(ctx, obj, patcher); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
 	}
 
+	// Report the pending revision without applying it in read-only mode.
+	if r.ReadOnly {
+		msg := fmt.Sprintf("Revision %s is up to date, apply skipped in read-only mode", buildResult.Revision)
+		if obj.Status.LastAppliedRevision != buildResult.Revision {
+			msg = fmt.Sprintf("Revision %s is pending, apply skipped in read-only mode", buildResult.Revision)
+		}
+		conditions.MarkUnknown(obj,
+			meta.ReadyCondition,
+			fluxcdv1.ReadOnlyReason,
+			"%s", msg)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		log.Info(msg)
+		r.EventRecorder.Event(obj, corev1.EventTypeNormal, fluxcdv1.ReadOnlyReason, msg)
+		return requeueAfter(obj), nil
+	}
+
+	// Wait for a manual approval before applying a new revision.
+	if buildResult.Revision != obj.Status.LastAppliedRevision && !obj.IsApproved(buildResult.Revision) {
+		msg := fmt.Sprintf("Revision %s is awaiting approval", buildResult.Revision)
+		if obj.Status.LastAppliedRevision != "" {
+			msg = fmt.Sprintf("Upgrade from %s to %s is awaiting approval",
+				obj.Status.LastAppliedRevision, buildResult.Revision)
+		}
+		conditions.MarkTrue(obj,
+			fluxcdv1.AwaitingApprovalCondition,
+			fluxcdv1.ApprovalPendingReason,
+			"%s", msg)
+		conditions.MarkUnknown(obj,
+			meta.ReadyCondition,
+			fluxcdv1.ApprovalPendingReason,
+			"%s", msg)
+		conditions.Delete(obj, meta.ReconcilingCondition)
+		log.Info(msg)
+		r.notify(ctx, obj, fluxcdv1.ApprovalPendingReason, corev1.EventTypeNormal, msg)
+		return requeueAfter(obj), nil
+	}
+
+	// Record the approver of the new revision. The approver name is taken
+	// from the annotation as is, it's not verified against the user identity.
+	if obj.Spec.ApprovalRequired && buildResult.Revision != obj.Status.LastAppliedRevision {
+		approver := obj.GetAnnotations()[fluxcdv1.ApprovedByAnnotation]
+		msg := fmt.Sprintf("Revision %s approved", buildResult.Revision)
+		if approver != "" {
+			msg = fmt.Sprintf("Revision %s approved by %s (unverified)", buildResult.Revision, approver)
+		}
+		obj.Status.LastApprovedBy = approver
+		log.Info(msg)
+		r.EventRecorder.Event(obj, corev1.EventTypeNormal, fluxcdv1.ApprovedReason, msg)
+	}
+
 	// Apply the distribution manifests.
 	if err := r.apply(ctx, obj, buildResult); err != nil {
 		msg := fmt.Sprintf("reconciliation failed: %s", err.Error())
@@ -200,8 +275,19 @@ func (r *FluxInstanceReconciler) reconcile(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
+	// Remove the approval gate once the approved revision is applied.
+	conditions.Delete(obj, fluxcdv1.AwaitingApprovalCondition)
+
+	// Verify that the tenants can't act outside their namespaces,
+	// only when the tenant set or the revision changed.
+	if r.tenantIsolationChanged(obj) {
+		r.verifyTenantIsolation(ctx, obj)
+	}
+
 	// Mark the object as ready.
 	obj.Status.LastAppliedRevision = obj.Status.LastAttemptedRevision
+	obj.Status.LastAppliedVersion = buildResult.Version
+	obj.Status.LastAppliedDigest = buildResult.Digest
 	obj.Status.LastArtifactRevision = artifactDigest
 	msg = fmt.Sprintf("Reconciliation finished in %s", fmtDuration(reconcileStart))
 	conditions.MarkTrue(obj,
@@ -232,7 +318,8 @@ func (r *FluxInstanceReconciler) fetch(ctx context.Context,
 		ctxPull, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		artifactDigest, err := builder.PullArtifact(ctxPull, artifactURL, tmpDir)
+		artifactDigest, err := builder.PullArtifact(ctxPull, artifactURL,
+			obj.Spec.Distribution.ArtifactMediaType, tmpDir)
 		if err != nil {
 			return "", "", err
 		}
@@ -291,6 +378,7 @@ func (r *FluxInstanceReconciler) build(ctx context.Context,
 	options := builder.MakeDefaultOptions()
 	options.Version = ver
 	options.Registry = obj.GetDistribution().Registry
+	options.RegistryMirror = obj.GetDistribution().Mirror
 	options.ImagePullSecret = obj.GetDistribution().ImagePullSecret
 	options.Namespace = obj.GetNamespace()
 	options.Components = obj.GetComponents()
@@ -305,6 +393,14 @@ func (r *FluxInstanceReconciler) build(ctx context.Context,
 	}
 	if obj.GetCluster().Multitenant {
 		options.Patches += builder.GetMultitenantProfile(obj.GetCluster().TenantDefaultServiceAccount)
+		for _, tenant := range obj.GetCluster().Tenants {
+			options.Tenants = append(options.Tenants, builder.Tenant{
+				Namespace:      tenant.Namespace,
+				ServiceAccount: tenant.ServiceAccount,
+				ResourceQuota:  tenant.ResourceQuota,
+				LimitRange:     tenant.LimitRange,
+			})
+		}
 	}
 
 	if builder.ContainElementString(options.Components, options.NotificationController) {
@@ -339,6 +435,26 @@ func (r *FluxInstanceReconciler) build(ctx context.Context,
 		}
 	}
 
+	for _, s := range obj.Spec.Syncs {
+		options.Syncs = append(options.Syncs, builder.Sync{
+			Name:       s.Name,
+			Kind:       s.Kind,
+			Interval:   s.Interval.Duration.String(),
+			Ref:        s.Ref,
+			PullSecret: s.PullSecret,
+			URL:        s.URL,
+			Path:       s.Path,
+		})
+	}
+
+	if len(obj.Spec.Profiles) > 0 {
+		profilesData, err := builder.GetProfiles(obj.Spec.Profiles)
+		if err != nil {
+			return nil, err
+		}
+		options.Patches += profilesData
+	}
+
 	if obj.Spec.Kustomize != nil && len(obj.Spec.Kustomize.Patches) > 0 {
 		patchesData, err := yaml.Marshal(obj.Spec.Kustomize.Patches)
 		if err != nil {
@@ -358,7 +474,105 @@ func (r *FluxInstanceReconciler) build(ctx context.Context,
 	}
 	options.ComponentImages = images
 
-	return builder.Build(srcDir, tmpDir, options)
+	result, err := builder.Build(srcDir, tmpDir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// verifyImageArchitectures checks that the component images pinned by digest
+// are available for all the CPU architectures of the cluster nodes.
+// For every component missing an architecture, a warning event is recorded.
+func (r *FluxInstanceReconciler) verifyImageArchitectures(ctx context.Context,
+	obj *fluxcdv1.FluxInstance, images []builder.ComponentImage) {
+	log := ctrl.LoggerFrom(ctx)
+
+	nodes := &metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+	if err := r.reader().List(ctx, nodes); err != nil {
+		log.Error(err, "failed to list nodes, skipping image architecture verification")
+		return
+	}
+
+	var clusterArchs []string
+	for _, node := range nodes.Items {
+		arch := node.GetLabels()[corev1.LabelArchStable]
+		if arch != "" && !slices.Contains(clusterArchs, arch) {
+			clusterArchs = append(clusterArchs, arch)
+		}
+	}
+	if len(clusterArchs) == 0 {
+		return
+	}
+
+	keychain, err := r.imagePullKeychain(ctx, obj)
+	if err != nil {
+		log.Error(err, "failed to read the image pull secret, skipping image architecture verification")
+		return
+	}
+
+	ctxImg, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	for _, img := range images {
+		if img.Digest == "" {
+			continue
+		}
+
+		// The digests are immutable, so the architectures are looked up only once.
+		key := fmt.Sprintf("%s@%s", img.Repository, img.Digest)
+		var imageArchs []string
+		if cached, ok := r.imageArchs.Load(key); ok {
+			imageArchs = cached.([]string)
+		} else {
+			imageArchs, err = builder.GetImageArchitectures(ctxImg, img, keychain)
+			if err != nil {
+				log.Error(err, "failed to verify image architectures", "component", img.Name)
+				continue
+			}
+			r.imageArchs.Store(key, imageArchs)
+		}
+
+		if missing := builder.MissingArchitectures(imageArchs, clusterArchs); len(missing) > 0 {
+			msg := fmt.Sprintf("Image %s:%s@%s is not available for architectures: %s",
+				img.Repository, img.Tag, img.Digest, strings.Join(missing, ", "))
+			log.Info(msg, "component", img.Name)
+			r.Event(obj, corev1.EventTypeWarning, fluxcdv1.MissingArchitectureReason, msg)
+		}
+	}
+}
+
+// imagePullKeychain returns the registry keychain built from the
+// distribution image pull secret, or nil if no secret is specified.
+func (r *FluxInstanceReconciler) imagePullKeychain(ctx context.Context,
+	obj *fluxcdv1.FluxInstance) (authn.Keychain, error) {
+	secretName := obj.GetDistribution().ImagePullSecret
+	if secretName == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: secretName}
+	if err := r.reader().Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+
+	data, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing the %s key", key, corev1.DockerConfigJsonKey)
+	}
+
+	return builder.NewDockerConfigKeychain(data)
+}
+
+// reader returns the API reader if set, otherwise the client.
+func (r *FluxInstanceReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
 }
 
 // apply reconciles the resources in the cluster by performing
@@ -472,6 +686,9 @@ func (r *FluxInstanceReconciler) apply(ctx context.Context,
 		}
 	}
 
+	// Set the FIPS compliance condition based on the component images.
+	setFIPSCondition(obj, buildResult.ComponentImages)
+
 	// Detect stale resources which are subject to garbage collection.
 	staleObjects, err := inventory.Diff(oldInventory, newInventory)
 	if err != nil {
@@ -573,6 +790,9 @@ func (r *FluxInstanceReconciler) patch(ctx context.Context,
 		meta.ReadyCondition,
 		meta.ReconcilingCondition,
 		meta.StalledCondition,
+		fluxcdv1.FIPSCompliantCondition,
+		fluxcdv1.TenantIsolationCondition,
+		fluxcdv1.AwaitingApprovalCondition,
 	}
 	patchOpts := []patch.Option{
 		patch.WithOwnedConditions{Conditions: ownedConditions},
@@ -607,6 +827,31 @@ func hasChanged(action ssa.Action) bool {
 	}
 }
 
+// setFIPSCondition marks the object as FIPS-compliant if all
+// the component images are FIPS builds, otherwise it lists
+// the non-compliant components in the condition message.
+func setFIPSCondition(obj *fluxcdv1.FluxInstance, images []builder.ComponentImage) {
+	var nonFIPS []string
+	for _, img := range images {
+		if !builder.IsFIPSImage(img) {
+			nonFIPS = append(nonFIPS, img.Name)
+		}
+	}
+
+	if len(nonFIPS) > 0 {
+		conditions.MarkFalse(obj,
+			fluxcdv1.FIPSCompliantCondition,
+			fluxcdv1.NonFIPSImagesReason,
+			"Components running non-FIPS images: %s", strings.Join(nonFIPS, ", "))
+		return
+	}
+
+	conditions.MarkTrue(obj,
+		fluxcdv1.FIPSCompliantCondition,
+		fluxcdv1.FIPSImagesReason,
+		"%s", "All components are running FIPS-compliant images")
+}
+
 // requeueAfter returns a ctrl.Result with the requeue time set to the
 // interval specified in the object's annotations.
 func requeueAfter(obj *fluxcdv1.FluxInstance) ctrl.Result {
@@ -15,7 +15,9 @@ import (
 	"github.com/fluxcd/pkg/runtime/conditions"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -25,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/builder"
 )
 
 func TestFluxInstanceReconciler_LifeCycle(t *testing.T) {
@@ -159,6 +162,10 @@ func TestFluxInstanceReconciler_LifeCycle(t *testing.T) {
 	logObjectStatus(t, resultFinal)
 	g.Expect(resultFinal.Status.LastAttemptedRevision).To(HavePrefix("v2.3.0@sha256:"))
 	g.Expect(resultFinal.Status.LastAppliedRevision).To(BeIdenticalTo(resultFinal.Status.LastAttemptedRevision))
+	g.Expect(resultFinal.Status.LastAppliedVersion).To(Equal("v2.3.0"))
+	g.Expect(resultFinal.Status.LastAppliedDigest).To(HavePrefix("sha256:"))
+	g.Expect(resultFinal.Status.LastAppliedRevision).To(Equal(
+		fmt.Sprintf("%s@%s", resultFinal.Status.LastAppliedVersion, resultFinal.Status.LastAppliedDigest)))
 
 	// Check if the inventory was updated.
 	g.Expect(resultFinal.Status.Inventory.Entries).ToNot(ContainElements(
@@ -530,6 +537,201 @@ func TestFluxInstanceReconciler_Disabled(t *testing.T) {
 	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
 }
 
+func TestFluxInstanceReconciler_ReadOnly(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	reconciler.ReadOnly = true
+	spec := getDefaultFluxSpec(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: spec,
+	}
+
+	err = testClient.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile the instance in read-only mode.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the pending revision was reported.
+	result := &fluxcdv1.FluxInstance{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsUnknown(result, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(result, meta.ReadyCondition)).To(Equal(fluxcdv1.ReadOnlyReason))
+	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring("is pending"))
+	g.Expect(conditions.Has(result, meta.ReconcilingCondition)).To(BeFalse())
+	g.Expect(result.Status.LastAttemptedRevision).To(HavePrefix("v2.3.0@sha256:"))
+	g.Expect(result.Status.LastAppliedRevision).To(BeEmpty())
+	g.Expect(result.Status.Inventory).To(BeNil())
+
+	// Check that no resources were applied.
+	sc := &appsv1.Deployment{}
+	err = testClient.Get(ctx, types.NamespacedName{Name: "source-controller", Namespace: ns.Name}, sc)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Check that the uninstall is skipped in read-only mode.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Finalizers).To(ContainElement(fluxcdv1.Finalizer))
+
+	events := getEvents(result.Name)
+	g.Expect(events[len(events)-1].Reason).To(Equal(fluxcdv1.ReadOnlyReason))
+
+	// Finalize the instance with read-only mode disabled.
+	reconciler.ReadOnly = false
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestFluxInstanceReconciler_ApprovalRequired(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	spec := getDefaultFluxSpec(t)
+	spec.ApprovalRequired = true
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: spec,
+	}
+
+	err = testClient.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile the instance without approval.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the instance is awaiting approval.
+	result := &fluxcdv1.FluxInstance{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsTrue(result, fluxcdv1.AwaitingApprovalCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(result, fluxcdv1.AwaitingApprovalCondition)).To(ContainSubstring(result.Status.LastAttemptedRevision))
+	g.Expect(conditions.GetReason(result, meta.ReadyCondition)).To(Equal(fluxcdv1.ApprovalPendingReason))
+	g.Expect(result.Status.LastAppliedRevision).To(BeEmpty())
+
+	// Check that no resources were applied.
+	sc := &appsv1.Deployment{}
+	err = testClient.Get(ctx, types.NamespacedName{Name: "source-controller", Namespace: ns.Name}, sc)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Approve a different revision.
+	resultP := result.DeepCopy()
+	resultP.SetAnnotations(
+		map[string]string{
+			fluxcdv1.ApprovedRevisionAnnotation: "v2.2.0@sha256:unknown",
+		})
+	err = testClient.Patch(ctx, resultP, client.MergeFrom(result))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check that the instance is still awaiting approval.
+	result = &fluxcdv1.FluxInstance{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsTrue(result, fluxcdv1.AwaitingApprovalCondition)).To(BeTrue())
+	g.Expect(result.Status.LastAppliedRevision).To(BeEmpty())
+
+	// Approve the pending revision.
+	resultP = result.DeepCopy()
+	resultP.SetAnnotations(
+		map[string]string{
+			fluxcdv1.ApprovedRevisionAnnotation: result.Status.LastAttemptedRevision,
+			fluxcdv1.ApprovedByAnnotation:       "platform-admin",
+		})
+	err = testClient.Patch(ctx, resultP, client.MergeFrom(result))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Reconcile the instance with approval.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the instance was installed.
+	resultFinal := &fluxcdv1.FluxInstance{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), resultFinal)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	checkInstanceReadiness(g, resultFinal)
+	g.Expect(conditions.Has(resultFinal, fluxcdv1.AwaitingApprovalCondition)).To(BeFalse())
+	g.Expect(resultFinal.Status.LastAppliedRevision).To(Equal(result.Status.LastAttemptedRevision))
+	g.Expect(resultFinal.Status.LastApprovedBy).To(Equal("platform-admin"))
+
+	err = testClient.Get(ctx, types.NamespacedName{Name: "source-controller", Namespace: ns.Name}, sc)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Uninstall the instance.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
 func TestFluxInstanceReconciler_Profiles(t *testing.T) {
 	g := NewWithT(t)
 	reconciler := getFluxInstanceReconciler()
@@ -640,6 +842,88 @@ func TestFluxInstanceReconciler_Profiles(t *testing.T) {
 	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
 }
 
+func TestFluxInstanceReconciler_Syncs(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: getDefaultFluxSpec(t),
+	}
+
+	// Verify that duplicate sync names are rejected.
+	obj.Spec.Syncs = []fluxcdv1.SyncSource{
+		{Name: "apps", Kind: "GitRepository", URL: "https://host/apps.git", Ref: "refs/heads/main", Path: "./"},
+		{Name: "apps", Kind: "OCIRepository", URL: "oci://registry/apps", Ref: "latest", Path: "./"},
+	}
+	err = testClient.Create(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("Sync names must be unique"))
+
+	obj.Spec.Syncs[1].Name = "infra"
+	err = testClient.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if a source and Kustomization were generated for each sync.
+	result := &fluxcdv1.FluxInstance{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	checkInstanceReadiness(g, result)
+	g.Expect(result.Status.Inventory.Entries).To(ContainElements(
+		fluxcdv1.ResourceRef{
+			ID:      fmt.Sprintf("%[1]s_%[1]s_source.toolkit.fluxcd.io_OCIRepository", ns.Name),
+			Version: "v1beta2",
+		},
+		fluxcdv1.ResourceRef{
+			ID:      fmt.Sprintf("%s_apps_source.toolkit.fluxcd.io_GitRepository", ns.Name),
+			Version: "v1",
+		},
+		fluxcdv1.ResourceRef{
+			ID:      fmt.Sprintf("%s_apps_kustomize.toolkit.fluxcd.io_Kustomization", ns.Name),
+			Version: "v1",
+		},
+		fluxcdv1.ResourceRef{
+			ID:      fmt.Sprintf("%s_infra_source.toolkit.fluxcd.io_OCIRepository", ns.Name),
+			Version: "v1beta2",
+		},
+		fluxcdv1.ResourceRef{
+			ID:      fmt.Sprintf("%s_infra_kustomize.toolkit.fluxcd.io_Kustomization", ns.Name),
+			Version: "v1",
+		},
+	))
+
+	// Uninstall the instance.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
 func TestFluxInstanceReconciler_NewVersion(t *testing.T) {
 	g := NewWithT(t)
 	reconciler := getFluxInstanceReconciler()
@@ -692,6 +976,309 @@ func TestFluxInstanceReconciler_NewVersion(t *testing.T) {
 
 }
 
+func TestFluxInstanceReconciler_FIPSCondition(t *testing.T) {
+	g := NewWithT(t)
+	obj := &fluxcdv1.FluxInstance{}
+
+	setFIPSCondition(obj, []builder.ComponentImage{
+		{
+			Name:       "source-controller",
+			Repository: "ghcr.io/controlplaneio-fluxcd/distroless/source-controller",
+			Tag:        "v1.3.0",
+		},
+		{
+			Name:       "kustomize-controller",
+			Repository: "ghcr.io/controlplaneio-fluxcd/distroless/kustomize-controller",
+			Tag:        "v1.3.0",
+		},
+	})
+	g.Expect(conditions.IsTrue(obj, fluxcdv1.FIPSCompliantCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.FIPSCompliantCondition)).To(Equal(fluxcdv1.FIPSImagesReason))
+
+	setFIPSCondition(obj, []builder.ComponentImage{
+		{
+			Name:       "source-controller",
+			Repository: "ghcr.io/controlplaneio-fluxcd/distroless/source-controller",
+			Tag:        "v1.3.0",
+		},
+		{
+			Name:       "kustomize-controller",
+			Repository: "ghcr.io/fluxcd/kustomize-controller",
+			Tag:        "v1.3.0",
+		},
+	})
+	g.Expect(conditions.IsFalse(obj, fluxcdv1.FIPSCompliantCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.FIPSCompliantCondition)).To(Equal(fluxcdv1.NonFIPSImagesReason))
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.FIPSCompliantCondition)).To(ContainSubstring("kustomize-controller"))
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.FIPSCompliantCondition)).ToNot(ContainSubstring("source-controller"))
+
+	// Verify that the '-fips' tag suffix alone doesn't mark the images as compliant.
+	setFIPSCondition(obj, []builder.ComponentImage{
+		{
+			Name:       "source-controller",
+			Repository: "registry.example.com/fluxcd/source-controller",
+			Tag:        "v1.3.0-fips",
+		},
+	})
+	g.Expect(conditions.IsFalse(obj, fluxcdv1.FIPSCompliantCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.FIPSCompliantCondition)).To(Equal(fluxcdv1.NonFIPSImagesReason))
+}
+
+func TestFluxInstanceReconciler_TenantIsolation(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+	team1, err := testEnv.CreateNamespace(ctx, "team1")
+	g.Expect(err).ToNot(HaveOccurred())
+	team2, err := testEnv.CreateNamespace(ctx, "team2")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: fluxcdv1.FluxInstanceSpec{
+			Cluster: &fluxcdv1.Cluster{
+				Multitenant:           true,
+				VerifyTenantIsolation: true,
+				Tenants: []fluxcdv1.Tenant{
+					{Namespace: team1.Name, ServiceAccount: "flux"},
+					{Namespace: team2.Name, ServiceAccount: "flux"},
+				},
+			},
+		},
+	}
+
+	// Grant the tenants access to their own namespaces only.
+	for _, tenant := range obj.Spec.Cluster.Tenants {
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-tenant",
+				Namespace: tenant.Namespace,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     "admin",
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      tenant.ServiceAccount,
+					Namespace: tenant.Namespace,
+				},
+			},
+		}
+		err = testClient.Create(ctx, rb)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	reconciler.verifyTenantIsolation(ctx, obj)
+	g.Expect(conditions.IsTrue(obj, fluxcdv1.TenantIsolationCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.TenantIsolationCondition)).To(Equal(fluxcdv1.TenantsIsolatedReason))
+
+	// Grant the team1 tenant access to the team2 namespace.
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cross-tenant",
+			Namespace: team2.Name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "flux",
+				Namespace: team1.Name,
+			},
+		},
+	}
+	err = testClient.Create(ctx, rb)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler.verifyTenantIsolation(ctx, obj)
+	g.Expect(conditions.IsFalse(obj, fluxcdv1.TenantIsolationCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.TenantIsolationCondition)).To(Equal(fluxcdv1.TenantIsolationBrokenReason))
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.TenantIsolationCondition)).To(
+		ContainSubstring(fmt.Sprintf("%s/flux can get secrets in namespace %s", team1.Name, team2.Name)))
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.TenantIsolationCondition)).ToNot(
+		ContainSubstring(fmt.Sprintf("%s/flux", team2.Name)))
+
+	// Grant the team2 tenant access at cluster scope.
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-cluster-admin", team2.Name),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "flux",
+				Namespace: team2.Name,
+			},
+		},
+	}
+	err = testClient.Create(ctx, crb)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciler.verifyTenantIsolation(ctx, obj)
+	g.Expect(conditions.IsFalse(obj, fluxcdv1.TenantIsolationCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.TenantIsolationCondition)).To(
+		ContainSubstring(fmt.Sprintf("%s/flux can list secrets at cluster scope", team2.Name)))
+
+	err = testClient.Delete(ctx, crb)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Disable the verification.
+	obj.Spec.Cluster.VerifyTenantIsolation = false
+	reconciler.verifyTenantIsolation(ctx, obj)
+	g.Expect(conditions.Has(obj, fluxcdv1.TenantIsolationCondition)).To(BeFalse())
+}
+
+func TestFluxInstanceReconciler_ImagePullKeychain(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: getDefaultFluxSpec(t),
+	}
+
+	// No keychain without a pull secret.
+	keychain, err := reconciler.imagePullKeychain(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keychain).To(BeNil())
+
+	// Fail if the pull secret is missing.
+	obj.Spec.Distribution.ImagePullSecret = "registry-auth"
+	_, err = reconciler.imagePullKeychain(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-auth",
+			Namespace: ns.Name,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"username":"flux","password":"token"}}}`),
+		},
+	}
+	err = testClient.Create(ctx, secret)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	keychain, err = reconciler.imagePullKeychain(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keychain).ToNot(BeNil())
+}
+
+func TestFluxInstanceReconciler_TenantIsolationChanged(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flux",
+			Namespace: "flux-system",
+		},
+		Spec: fluxcdv1.FluxInstanceSpec{
+			Cluster: &fluxcdv1.Cluster{
+				Multitenant:           true,
+				VerifyTenantIsolation: true,
+				Tenants: []fluxcdv1.Tenant{
+					{Namespace: "team1", ServiceAccount: "flux"},
+				},
+			},
+		},
+	}
+	obj.Status.LastAttemptedRevision = "v2.3.0@sha256:1"
+
+	// The first verification is always performed.
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeTrue())
+	conditions.MarkTrue(obj, fluxcdv1.TenantIsolationCondition, fluxcdv1.TenantsIsolatedReason, "isolated")
+
+	// Skip the verification if nothing changed.
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeFalse())
+
+	// Verify again when the revision changes.
+	obj.Status.LastAttemptedRevision = "v2.3.0@sha256:2"
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeTrue())
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeFalse())
+
+	// Verify again when the tenant set changes.
+	obj.Spec.Cluster.Tenants = append(obj.Spec.Cluster.Tenants, fluxcdv1.Tenant{Namespace: "team2"})
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeTrue())
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeFalse())
+
+	// Verify again if the last verification failed.
+	conditions.MarkUnknown(obj, fluxcdv1.TenantIsolationCondition,
+		fluxcdv1.TenantIsolationVerificationFailedReason, "failed")
+	g.Expect(reconciler.tenantIsolationChanged(obj)).To(BeTrue())
+}
+
+// failingSARClient fails the creation of SubjectAccessReviews.
+type failingSARClient struct {
+	client.Client
+}
+
+func (c *failingSARClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+		return fmt.Errorf("subjectaccessreviews is forbidden")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestFluxInstanceReconciler_TenantIsolationVerificationFailed(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getFluxInstanceReconciler()
+	reconciler.Client = &failingSARClient{Client: testClient}
+
+	obj := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flux",
+			Namespace: "flux-system",
+		},
+		Spec: fluxcdv1.FluxInstanceSpec{
+			Cluster: &fluxcdv1.Cluster{
+				Multitenant:           true,
+				VerifyTenantIsolation: true,
+				Tenants: []fluxcdv1.Tenant{
+					{Namespace: "team1", ServiceAccount: "flux"},
+					{Namespace: "team2", ServiceAccount: "flux"},
+				},
+			},
+		},
+	}
+
+	reconciler.verifyTenantIsolation(context.Background(), obj)
+	g.Expect(conditions.IsTrue(obj, fluxcdv1.TenantIsolationCondition)).To(BeFalse())
+	g.Expect(conditions.IsUnknown(obj, fluxcdv1.TenantIsolationCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, fluxcdv1.TenantIsolationCondition)).To(
+		Equal(fluxcdv1.TenantIsolationVerificationFailedReason))
+	g.Expect(conditions.GetMessage(obj, fluxcdv1.TenantIsolationCondition)).To(
+		ContainSubstring("subjectaccessreviews is forbidden"))
+}
+
 func getDefaultFluxSpec(t *testing.T) fluxcdv1.FluxInstanceSpec {
 	// Disable notifications for the tests as no pod is running.
 	// This is required to avoid the 30s retry loop performed by the HTTP client.
@@ -0,0 +1,196 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// tenantIsolationProbes is the sample of resource attributes used
+// to check if a tenant service account can act outside its namespace.
+var tenantIsolationProbes = []authorizationv1.ResourceAttributes{
+	{Verb: "get", Resource: "secrets"},
+	{Verb: "create", Group: "apps", Resource: "deployments"},
+}
+
+// tenantClusterProbes is the sample of resource attributes used
+// to check if a tenant service account can act at cluster scope.
+var tenantClusterProbes = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Resource: "secrets"},
+	{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+}
+
+// tenantIsolationChanged returns true if the tenant isolation must be verified,
+// i.e. the tenant set or the revision changed since the last verification,
+// or the last verification failed. When the verification is disabled,
+// it returns true for the condition to be removed.
+func (r *FluxInstanceReconciler) tenantIsolationChanged(obj *fluxcdv1.FluxInstance) bool {
+	cluster := obj.GetCluster()
+	if !cluster.Multitenant || !cluster.VerifyTenantIsolation || len(cluster.Tenants) == 0 {
+		r.tenantIsolation.Delete(client.ObjectKeyFromObject(obj))
+		return true
+	}
+
+	tenants := make([]string, 0, len(cluster.Tenants))
+	for _, tenant := range cluster.Tenants {
+		tenants = append(tenants, fmt.Sprintf("%s/%s", tenant.Namespace, tenantServiceAccount(cluster, tenant)))
+	}
+	key := fmt.Sprintf("%s|%s", obj.Status.LastAttemptedRevision, strings.Join(tenants, ","))
+
+	if conditions.Has(obj, fluxcdv1.TenantIsolationCondition) &&
+		!conditions.IsUnknown(obj, fluxcdv1.TenantIsolationCondition) {
+		if last, ok := r.tenantIsolation.Load(client.ObjectKeyFromObject(obj)); ok && last.(string) == key {
+			return false
+		}
+	}
+
+	r.tenantIsolation.Store(client.ObjectKeyFromObject(obj), key)
+	return true
+}
+
+// verifyTenantIsolation checks with SubjectAccessReviews that the tenant
+// service accounts can't access resources in the instance namespace,
+// in the other tenant namespaces or at cluster scope,
+// and sets the TenantIsolation condition.
+// If any of the access reviews fails, the condition is set to Unknown.
+// If the verification is disabled, the condition is removed.
+func (r *FluxInstanceReconciler) verifyTenantIsolation(ctx context.Context,
+	obj *fluxcdv1.FluxInstance) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cluster := obj.GetCluster()
+	if !cluster.Multitenant || !cluster.VerifyTenantIsolation || len(cluster.Tenants) == 0 {
+		conditions.Delete(obj, fluxcdv1.TenantIsolationCondition)
+		return
+	}
+
+	namespaces := []string{obj.GetNamespace()}
+	for _, tenant := range cluster.Tenants {
+		namespaces = append(namespaces, tenant.Namespace)
+	}
+
+	var violations []string
+	var failures []string
+	for _, tenant := range cluster.Tenants {
+		sa := tenantServiceAccount(cluster, tenant)
+		for _, ns := range namespaces {
+			if ns == tenant.Namespace {
+				continue
+			}
+
+			for _, probe := range tenantIsolationProbes {
+				attrs := probe
+				attrs.Namespace = ns
+
+				allowed, err := r.isServiceAccountAllowed(ctx, tenant.Namespace, sa, attrs)
+				if err != nil {
+					log.Error(err, "failed to verify tenant isolation",
+						"tenant", tenant.Namespace, "serviceAccount", sa)
+					failures = append(failures, fmt.Sprintf("%s/%s in namespace %s: %s",
+						tenant.Namespace, sa, ns, err.Error()))
+					continue
+				}
+
+				if allowed {
+					violations = append(violations,
+						fmt.Sprintf("%s/%s can %s %s in namespace %s",
+							tenant.Namespace, sa, attrs.Verb, attrs.Resource, ns))
+					break
+				}
+			}
+		}
+
+		// An empty namespace checks the access at cluster scope.
+		for _, attrs := range tenantClusterProbes {
+			allowed, err := r.isServiceAccountAllowed(ctx, tenant.Namespace, sa, attrs)
+			if err != nil {
+				log.Error(err, "failed to verify tenant isolation",
+					"tenant", tenant.Namespace, "serviceAccount", sa)
+				failures = append(failures, fmt.Sprintf("%s/%s at cluster scope: %s",
+					tenant.Namespace, sa, err.Error()))
+				continue
+			}
+
+			if allowed {
+				violations = append(violations,
+					fmt.Sprintf("%s/%s can %s %s at cluster scope",
+						tenant.Namespace, sa, attrs.Verb, attrs.Resource))
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		msg := fmt.Sprintf("Tenant isolation is broken: %s", strings.Join(violations, ", "))
+		conditions.MarkFalse(obj,
+			fluxcdv1.TenantIsolationCondition,
+			fluxcdv1.TenantIsolationBrokenReason,
+			"%s", msg)
+		log.Info(msg)
+		r.Event(obj, corev1.EventTypeWarning, fluxcdv1.TenantIsolationBrokenReason, msg)
+		return
+	}
+
+	// Don't report the tenants as isolated if any of the probes failed.
+	if len(failures) > 0 {
+		msg := fmt.Sprintf("Tenant isolation verification failed: %s", strings.Join(failures, ", "))
+		conditions.MarkUnknown(obj,
+			fluxcdv1.TenantIsolationCondition,
+			fluxcdv1.TenantIsolationVerificationFailedReason,
+			"%s", msg)
+		r.Event(obj, corev1.EventTypeWarning, fluxcdv1.TenantIsolationVerificationFailedReason, msg)
+		return
+	}
+
+	conditions.MarkTrue(obj,
+		fluxcdv1.TenantIsolationCondition,
+		fluxcdv1.TenantsIsolatedReason,
+		"%s", "All tenant service accounts are restricted to their namespaces")
+}
+
+// isServiceAccountAllowed performs a SubjectAccessReview for the given
+// service account and returns true if the action is allowed.
+func (r *FluxInstanceReconciler) isServiceAccountAllowed(ctx context.Context,
+	namespace, name string, attrs authorizationv1.ResourceAttributes) (bool, error) {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name),
+			Groups: []string{
+				"system:serviceaccounts",
+				fmt.Sprintf("system:serviceaccounts:%s", namespace),
+				"system:authenticated",
+			},
+			ResourceAttributes: &attrs,
+		},
+	}
+
+	if err := r.Create(ctx, sar); err != nil {
+		return false, err
+	}
+
+	return sar.Status.Allowed, nil
+}
+
+// tenantServiceAccount returns the name of the service account
+// used by Flux when reconciling resources in the tenant namespace.
+func tenantServiceAccount(cluster fluxcdv1.Cluster, tenant fluxcdv1.Tenant) string {
+	switch {
+	case tenant.ServiceAccount != "":
+		return tenant.ServiceAccount
+	case cluster.TenantDefaultServiceAccount != "":
+		return cluster.TenantDefaultServiceAccount
+	default:
+		return "default"
+	}
+}
@@ -31,9 +31,12 @@ type FluxReportReconciler struct {
 	client.Client
 	kuberecorder.EventRecorder
 
-	Scheme         *runtime.Scheme
-	StatusManager  string
-	WatchNamespace string
+	Scheme            *runtime.Scheme
+	StatusManager     string
+	WatchNamespace    string
+	ReportConcurrency int
+	ReportBatchSize   int64
+	ReadOnly          bool
 }
 
 // +kubebuilder:rbac:groups=fluxcd.controlplane.io,resources=fluxreports,verbs=get;list;watch;create;update;patch;delete
@@ -48,6 +51,12 @@ func (r *FluxReportReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	obj := &fluxcdv1.FluxReport{}
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
 		if errors.IsNotFound(err) {
+			// Skip the initialization in read-only mode.
+			if r.ReadOnly {
+				log.Info("FluxReport not found, initialization skipped in read-only mode")
+				return ctrl.Result{}, nil
+			}
+
 			// Initialize the FluxReport if it doesn't exist.
 			err = r.initReport(ctx, fluxcdv1.DefaultInstanceName, r.WatchNamespace)
 			if err != nil {
@@ -68,12 +77,33 @@ func (r *FluxReportReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	patcher := patch.NewSerialPatcher(obj, r.Client)
 
 	// Compute the status of the Flux instance.
-	rep := reporter.NewFluxStatusReporter(r.Client, fluxcdv1.DefaultInstanceName, r.StatusManager, obj.Namespace)
+	rep := reporter.NewFluxStatusReporter(r.Client, fluxcdv1.DefaultInstanceName, r.StatusManager, obj.Namespace).
+		WithConcurrency(r.ReportConcurrency, r.ReportBatchSize)
+	if obj.Spec.Since != nil {
+		rep = rep.WithSince(obj.Spec.Since.Duration)
+	}
 	report, err := rep.Compute(ctx)
 	if err != nil {
 		log.Error(err, "report computed with errors")
 	}
 
+	// Preserve the user settings.
+	report.Since = obj.Spec.Since
+
+	// Record the metrics and compare the failing resources with the
+	// previous report, skipping partial reports to avoid false transitions.
+	if err == nil {
+		reporter.RecordReportMetrics(report)
+
+		// The failing resources of the previous report are persisted in
+		// the object status, the delta is skipped for the first report.
+		failing := rep.FailingResources()
+		if conditions.Has(obj, meta.ReadyCondition) {
+			report.Delta = reporter.ComputeDelta(obj.Status.FailingResources, failing)
+		}
+		obj.Status.FailingResources = failing
+	}
+
 	// Update the FluxReport with the computed spec.
 	obj.Spec = report
 
@@ -104,8 +134,11 @@ func (r *FluxReportReconciler) SetupWithManager(mgr ctrl.Manager, opts FluxRepor
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	if err := r.initReport(ctx, fluxcdv1.DefaultInstanceName, r.WatchNamespace); err != nil {
-		return fmt.Errorf("failed to initialize FluxReport: %w", err)
+	// Skip the initialization in read-only mode to avoid mutating the cluster.
+	if !r.ReadOnly {
+		if err := r.initReport(ctx, fluxcdv1.DefaultInstanceName, r.WatchNamespace); err != nil {
+			return fmt.Errorf("failed to initialize FluxReport: %w", err)
+		}
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
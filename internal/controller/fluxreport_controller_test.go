@@ -10,6 +10,7 @@ import (
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -228,6 +229,49 @@ func TestFluxReportReconciler_CustomSyncName(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 }
 
+func TestFluxReportReconciler_ReadOnly(t *testing.T) {
+	g := NewWithT(t)
+	reportRec := getFluxReportReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reportRec.ReadOnly = true
+	reportRec.WatchNamespace = ns.Name
+
+	report := &fluxcdv1.FluxReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fluxcdv1.DefaultInstanceName,
+			Namespace: ns.Name,
+		},
+	}
+
+	// Verify that the report is not initialized in read-only mode.
+	r, err := reportRec.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(report),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeFalse())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(report), report)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Verify that an existing report is still computed in read-only mode.
+	err = reportRec.initReport(ctx, report.GetName(), report.GetNamespace())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = reportRec.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(report),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(report), report)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsReady(report)).To(BeTrue())
+}
+
 func getFluxReportReconciler() *FluxReportReconciler {
 	return &FluxReportReconciler{
 		Client:        testClient,
@@ -236,3 +280,81 @@ func getFluxReportReconciler() *FluxReportReconciler {
 		StatusManager: controllerName,
 	}
 }
+
+func TestFluxReportReconciler_DeltaAfterRestart(t *testing.T) {
+	g := NewWithT(t)
+	instRec := getFluxInstanceReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	report := &fluxcdv1.FluxReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fluxcdv1.DefaultInstanceName,
+			Namespace: ns.Name,
+		},
+	}
+	err = getFluxReportReconciler().initReport(ctx, report.GetName(), report.GetNamespace())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Install Flux to register the reconcilers CRDs.
+	instance := &fluxcdv1.FluxInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		},
+		Spec: getDefaultFluxSpec(t),
+	}
+	err = testEnv.Create(ctx, instance)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = instRec.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(instance),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = instRec.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(instance),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Compute the first report, no delta is expected.
+	_, err = getFluxReportReconciler().Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(report),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(report), report)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Spec.Delta).To(BeNil())
+
+	// Simulate a resource that was failing before the operator restart.
+	stale := "Kustomization/" + ns.Name + "/stale"
+	report.Status.FailingResources = append(report.Status.FailingResources, stale)
+	err = testClient.Status().Update(ctx, report)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Compute the report with a new reconciler instance.
+	_, err = getFluxReportReconciler().Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(report),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(report), report)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Spec.Delta).ToNot(BeNil())
+	g.Expect(report.Spec.Delta.Recovered).To(ConsistOf(stale))
+	g.Expect(report.Spec.Delta.NewlyFailing).To(BeEmpty())
+	g.Expect(report.Status.FailingResources).ToNot(ContainElement(stale))
+
+	// Delete the instance.
+	err = testClient.Delete(ctx, instance)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = instRec.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(instance),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -5,7 +5,9 @@ package entitlement
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -76,15 +78,22 @@ func (c *AmazonClient) RegisterUsage(ctx context.Context, id string) (string, er
 
 // Verify verifies the JWT token is signed with the AWS Marketplace public key
 // and checks the product code, nonce and public key version claims.
+// If the token is authentic but expired, an ExpiredError is returned.
 func (c *AmazonClient) Verify(token, id string) (bool, error) {
 	t, err := jwt.ParseWithClaims(token, jwt.MapClaims{}, func(_ *jwt.Token) (any, error) {
 		return jwt.ParseRSAPublicKeyFromPEM([]byte(awsMarketplacePublicKey))
 	})
+	var expErr *ExpiredError
 	if err != nil {
-		return false, fmt.Errorf("AWS Marketplace invalid token: %w", err)
-	}
-
-	if !t.Valid {
+		// Allow the caller to apply a grace period if the token
+		// signature is valid and the expiration is the only error.
+		var ve *jwt.ValidationError
+		if !errors.As(err, &ve) || ve.Errors != jwt.ValidationErrorExpired {
+			return false, fmt.Errorf("AWS Marketplace invalid token: %w", err)
+		}
+		exp, _ := t.Claims.(jwt.MapClaims)["exp"].(float64)
+		expErr = &ExpiredError{ExpiresAt: time.Unix(int64(exp), 0)}
+	} else if !t.Valid {
 		return false, fmt.Errorf("AWS Marketplace invalid token")
 	}
 
@@ -96,6 +105,8 @@ func (c *AmazonClient) Verify(token, id string) (bool, error) {
 		return false, fmt.Errorf("AWS Marketplace nonce mismatch: %s", claims["nonce"])
 	case claims["publicKeyVersion"] != float64(awsMarketplacePublicKeyVersion):
 		return false, fmt.Errorf("AWS Marketplace public key version mismatch: %f", claims["publicKeyVersion"])
+	case expErr != nil:
+		return false, fmt.Errorf("AWS Marketplace %w", expErr)
 	}
 
 	return true, nil
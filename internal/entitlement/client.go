@@ -36,6 +36,7 @@ type Client interface {
 
 	// Verify verifies that the token is signed by the
 	// entitlement service and matches the usage id.
+	// If the token has expired, an ExpiredError is returned.
 	Verify(token, id string) (bool, error)
 
 	// GetVendor returns the vendor name.
@@ -45,11 +46,7 @@ type Client interface {
 // NewClient returns a new entitlement client based on the
 // marketplace type environment variable.
 func NewClient() (Client, error) {
-	vendor := DefaultVendor
-	marketplace, found := os.LookupEnv(MarketplaceTypeEnvKey)
-	if found && marketplace != "" && marketplace != DefaultVendor {
-		vendor = fmt.Sprintf("%s-%s", DefaultVendor, strings.ToLower(marketplace))
-	}
+	vendor := vendorFromEnv()
 
 	switch vendor {
 	case DefaultVendor:
@@ -60,3 +57,14 @@ func NewClient() (Client, error) {
 
 	return nil, fmt.Errorf("unsupported vendor %s", vendor)
 }
+
+// vendorFromEnv returns the vendor name based on
+// the marketplace type environment variable.
+func vendorFromEnv() string {
+	vendor := DefaultVendor
+	marketplace, found := os.LookupEnv(MarketplaceTypeEnvKey)
+	if found && marketplace != "" && marketplace != DefaultVendor {
+		vendor = fmt.Sprintf("%s-%s", DefaultVendor, strings.ToLower(marketplace))
+	}
+	return vendor
+}
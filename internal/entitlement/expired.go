@@ -0,0 +1,34 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"fmt"
+	"time"
+)
+
+// GracePeriodReason is the event reason recorded when an expired
+// entitlement is kept active during the grace period.
+const GracePeriodReason = "LicenseInGracePeriod"
+
+// GracePeriodAnnotation is set on the entitlement secret while an expired
+// entitlement is kept active, with the end of the grace period as value.
+const GracePeriodAnnotation = "fluxcd.controlplane.io/licenseGracePeriodEnd"
+
+// ExpiredError is returned by Verify when the token
+// is authentic but its expiration time has passed.
+type ExpiredError struct {
+	ExpiresAt time.Time
+}
+
+// Error returns the error message including the expiration time.
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("entitlement expired at %s", e.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+// InGracePeriod returns true if the expiration time
+// plus the grace period is after the given time.
+func (e *ExpiredError) InGracePeriod(gracePeriod time.Duration, now time.Time) bool {
+	return gracePeriod > 0 && now.Before(e.ExpiresAt.Add(gracePeriod))
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExpiredError(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	err := fmt.Errorf("verification failed: %w", &ExpiredError{ExpiresAt: now.Add(-time.Hour)})
+
+	var expErr *ExpiredError
+	g.Expect(errors.As(err, &expErr)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("entitlement expired at"))
+
+	g.Expect(expErr.InGracePeriod(0, now)).To(BeFalse())
+	g.Expect(expErr.InGracePeriod(30*time.Minute, now)).To(BeFalse())
+	g.Expect(expErr.InGracePeriod(2*time.Hour, now)).To(BeTrue())
+}
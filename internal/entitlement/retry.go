@@ -0,0 +1,103 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// DefaultBackoff is the backoff used to retry the entitlement
+// initialization and the usage registration on transient errors.
+var DefaultBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// IsTransientError returns true if the error is caused by a timeout,
+// throttling, an unavailable Kubernetes API server or AWS service,
+// or a network failure. Any other error is considered permanent
+// and should not be retried.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, ErrUnavailable) {
+		return false
+	}
+
+	if apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) {
+		return true
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	if awsretry.IsErrorThrottles(awsretry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary ||
+		awsretry.IsErrorRetryables(awsretry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// NewClientWithRetry calls the newClient function until it succeeds,
+// the error is not transient or the backoff steps are exhausted.
+// The onError function is called for every failed attempt
+// with the attempt number and the error.
+func NewClientWithRetry(backoff wait.Backoff,
+	newClient func() (Client, error),
+	onError func(attempt int, err error)) (Client, error) {
+	var client Client
+	attempt := 0
+	err := retry.OnError(backoff, IsTransientError, func() error {
+		attempt++
+		c, err := newClient()
+		if err != nil {
+			if onError != nil {
+				onError(attempt, err)
+			}
+			return err
+		}
+		client = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// RegisterUsageWithRetry calls the client RegisterUsage until it succeeds,
+// the error is not transient or the backoff steps are exhausted.
+func RegisterUsageWithRetry(ctx context.Context, backoff wait.Backoff, c Client, id string) (string, error) {
+	var token string
+	err := retry.OnError(backoff, IsTransientError, func() error {
+		t, err := c.RegisterUsage(ctx, id)
+		if err != nil {
+			return err
+		}
+		token = t
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/marketplacemetering/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestNewClientWithRetry(t *testing.T) {
+	g := NewWithT(t)
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0}
+
+	calls := 0
+	newClient := func() (Client, error) {
+		calls++
+		if calls < 3 {
+			return nil, apierrors.NewServiceUnavailable("transient error")
+		}
+		return &DefaultClient{Vendor: DefaultVendor}, nil
+	}
+
+	var attempts []int
+	client, err := NewClientWithRetry(backoff, newClient, func(attempt int, _ error) {
+		attempts = append(attempts, attempt)
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(client.GetVendor()).To(Equal(DefaultVendor))
+	g.Expect(attempts).To(Equal([]int{1, 2}))
+}
+
+func TestNewClientWithRetry_Exhausted(t *testing.T) {
+	g := NewWithT(t)
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0}
+
+	calls := 0
+	newClient := func() (Client, error) {
+		calls++
+		return nil, apierrors.NewTooManyRequests("throttled", 1)
+	}
+
+	client, err := NewClientWithRetry(backoff, newClient, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("throttled"))
+	g.Expect(client).To(BeNil())
+	g.Expect(calls).To(Equal(3))
+}
+
+func TestNewClientWithRetry_Permanent(t *testing.T) {
+	g := NewWithT(t)
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0}
+
+	calls := 0
+	newClient := func() (Client, error) {
+		calls++
+		return nil, errors.New("permanent error")
+	}
+
+	client, err := NewClientWithRetry(backoff, newClient, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("permanent error"))
+	g.Expect(client).To(BeNil())
+	g.Expect(calls).To(Equal(1))
+}
+
+func TestIsTransientError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "secrets"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "server timeout", err: apierrors.NewServerTimeout(gr, "get", 1), want: true},
+		{name: "timeout", err: apierrors.NewTimeoutError("timeout", 1), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("throttled", 1), want: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), want: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("etcd")), want: true},
+		{name: "deadline exceeded", err: fmt.Errorf("get secret: %w", context.DeadlineExceeded), want: true},
+		{name: "network", err: fmt.Errorf("get secret: %w", &net.OpError{Op: "dial", Err: errors.New("refused")}), want: true},
+		{name: "forbidden", err: apierrors.NewForbidden(gr, "entitlement", errors.New("rbac")), want: false},
+		{name: "not found", err: apierrors.NewNotFound(gr, "entitlement"), want: false},
+		{name: "generic", err: errors.New("unsupported vendor"), want: false},
+		{name: "aws throttling", err: awsOperationError(&types.ThrottlingException{Message: aws.String("rate exceeded")}), want: true},
+		{name: "aws service unavailable", err: awsOperationError(awsResponseError(http.StatusServiceUnavailable)), want: true},
+		{name: "aws internal error", err: awsOperationError(awsResponseError(http.StatusInternalServerError)), want: true},
+		{name: "aws bad request", err: awsOperationError(awsResponseError(http.StatusBadRequest)), want: false},
+		{name: "aws invalid product", err: awsOperationError(&types.InvalidProductCodeException{Message: aws.String("invalid")}), want: false},
+		{name: "aws disabled api", err: awsOperationError(&types.DisabledApiException{Message: aws.String("disabled")}), want: false},
+		{name: "unavailable client", err: fmt.Errorf("%w: %w", ErrUnavailable, &net.OpError{Op: "dial", Err: errors.New("refused")}), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsTransientError(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRegisterUsageWithRetry(t *testing.T) {
+	g := NewWithT(t)
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0}
+
+	c := &flakyClient{
+		errs: []error{
+			awsOperationError(&types.ThrottlingException{Message: aws.String("rate exceeded")}),
+			awsOperationError(awsResponseError(http.StatusServiceUnavailable)),
+		},
+	}
+
+	token, err := RegisterUsageWithRetry(context.Background(), backoff, c, "id")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token).To(Equal("token"))
+	g.Expect(c.calls).To(Equal(3))
+}
+
+func TestRegisterUsageWithRetry_Permanent(t *testing.T) {
+	g := NewWithT(t)
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0}
+
+	c := &flakyClient{
+		errs: []error{
+			awsOperationError(&types.InvalidProductCodeException{Message: aws.String("invalid")}),
+		},
+	}
+
+	token, err := RegisterUsageWithRetry(context.Background(), backoff, c, "id")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(token).To(BeEmpty())
+	g.Expect(c.calls).To(Equal(1))
+}
+
+// flakyClient is an entitlement client that fails the
+// usage registration with the given errors before succeeding.
+type flakyClient struct {
+	errs  []error
+	calls int
+}
+
+func (c *flakyClient) RegisterUsage(_ context.Context, _ string) (string, error) {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		return "", c.errs[c.calls-1]
+	}
+	return "token", nil
+}
+
+func (c *flakyClient) Verify(_, _ string) (bool, error) {
+	return true, nil
+}
+
+func (c *flakyClient) GetVendor() string {
+	return DefaultVendor
+}
+
+// awsOperationError wraps the error as returned by the AWS Marketplace metering client.
+func awsOperationError(err error) error {
+	return fmt.Errorf("failed to register usage with AWS Marketplace: %w", &smithy.OperationError{
+		ServiceID:     "Marketplace Metering",
+		OperationName: "RegisterUsage",
+		Err:           err,
+	})
+}
+
+// awsResponseError returns an AWS HTTP response error with the given status code.
+func awsResponseError(statusCode int) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: statusCode}},
+			Err:      errors.New(http.StatusText(statusCode)),
+		},
+		RequestID: "request-id",
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnavailable is returned by the UnavailableClient
+// when the entitlement service can't be reached.
+var ErrUnavailable = errors.New("entitlement client unavailable")
+
+// StatusAnnotation is set on the entitlement secret when the entitlement
+// state can't be determined, with the reason as value.
+const StatusAnnotation = "fluxcd.controlplane.io/licenseStatus"
+
+// UnavailableStatus is the entitlement status reported
+// in degraded mode when the entitlement service can't be reached.
+const UnavailableStatus = "Unknown: entitlement service unavailable"
+
+// UnavailableClient is an entitlement client used in degraded mode
+// when the client for the configured vendor fails to initialize.
+// This client returns ErrUnavailable for all registration and
+// verification requests.
+type UnavailableClient struct {
+	Vendor string
+	Err    error
+}
+
+// NewUnavailableClient returns an UnavailableClient for the vendor
+// set by the marketplace type environment variable.
+func NewUnavailableClient(err error) *UnavailableClient {
+	return &UnavailableClient{
+		Vendor: vendorFromEnv(),
+		Err:    err,
+	}
+}
+
+// RegisterUsage returns ErrUnavailable wrapping the initialization error.
+func (c *UnavailableClient) RegisterUsage(ctx context.Context, id string) (string, error) {
+	return "", c.error()
+}
+
+// Verify returns ErrUnavailable wrapping the initialization error.
+func (c *UnavailableClient) Verify(token, id string) (bool, error) {
+	return false, c.error()
+}
+
+// GetVendor returns the vendor name.
+func (c *UnavailableClient) GetVendor() string {
+	return c.Vendor
+}
+
+func (c *UnavailableClient) error() error {
+	if c.Err == nil {
+		return ErrUnavailable
+	}
+	return fmt.Errorf("%w: %w", ErrUnavailable, c.Err)
+}
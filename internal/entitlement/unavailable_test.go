@@ -0,0 +1,33 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUnavailableClient(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv(MarketplaceTypeEnvKey, "aws")
+
+	initErr := errors.New("failed to load AWS configuration")
+	client := NewUnavailableClient(initErr)
+	g.Expect(client.GetVendor()).To(Equal("controlplane-aws"))
+
+	token, err := client.RegisterUsage(context.Background(), "testID")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(errors.Is(err, ErrUnavailable)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring(initErr.Error()))
+	g.Expect(token).To(BeEmpty())
+
+	valid, err := client.Verify("testToken", "testID")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(errors.Is(err, ErrUnavailable)).To(BeTrue())
+	g.Expect(valid).To(BeFalse())
+}
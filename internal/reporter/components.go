@@ -7,6 +7,7 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
 	"golang.org/x/exp/slices"
@@ -57,3 +58,41 @@ func (r *FluxStatusReporter) getComponentsStatus(ctx context.Context) ([]fluxcdv
 
 	return components, nil
 }
+
+// setComponentsResources sets the number of total and failing resources
+// for each component based on the reconcilers statistics.
+func setComponentsResources(components []fluxcdv1.FluxComponentStatus, reconcilers []fluxcdv1.FluxReconcilerStatus) {
+	for i := range components {
+		components[i].Resources = 0
+		components[i].FailingResources = 0
+		for _, rs := range reconcilers {
+			if componentForKind(rs.APIVersion, rs.Kind) == components[i].Name {
+				components[i].Resources += rs.Stats.Running + rs.Stats.Suspended
+				components[i].FailingResources += rs.Stats.Failing
+			}
+		}
+	}
+}
+
+// componentForKind returns the name of the Flux controller
+// that reconciles the resources of the given API version and kind.
+func componentForKind(apiVersion, kind string) string {
+	group := strings.Split(apiVersion, "/")[0]
+	switch group {
+	case "source.toolkit.fluxcd.io":
+		return "source-controller"
+	case "kustomize.toolkit.fluxcd.io":
+		return "kustomize-controller"
+	case "helm.toolkit.fluxcd.io":
+		return "helm-controller"
+	case "notification.toolkit.fluxcd.io":
+		return "notification-controller"
+	case "image.toolkit.fluxcd.io":
+		if kind == "ImageUpdateAutomation" {
+			return "image-automation-controller"
+		}
+		return "image-reflector-controller"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package reporter
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestSetComponentsResources(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics["FluxComponent"])
+
+	components := []fluxcdv1.FluxComponentStatus{
+		{Name: "helm-controller"},
+		{Name: "image-automation-controller"},
+		{Name: "image-reflector-controller"},
+		{Name: "source-controller"},
+	}
+	reconcilers := []fluxcdv1.FluxReconcilerStatus{
+		{
+			APIVersion: "helm.toolkit.fluxcd.io/v2",
+			Kind:       "HelmRelease",
+			Stats:      fluxcdv1.FluxReconcilerStats{Running: 42, Failing: 1, Suspended: 3},
+		},
+		{
+			APIVersion: "image.toolkit.fluxcd.io/v1beta2",
+			Kind:       "ImagePolicy",
+			Stats:      fluxcdv1.FluxReconcilerStats{Running: 2, Failing: 1},
+		},
+		{
+			APIVersion: "image.toolkit.fluxcd.io/v1beta2",
+			Kind:       "ImageUpdateAutomation",
+			Stats:      fluxcdv1.FluxReconcilerStats{Running: 1},
+		},
+		{
+			APIVersion: "source.toolkit.fluxcd.io/v1",
+			Kind:       "GitRepository",
+			Stats:      fluxcdv1.FluxReconcilerStats{Running: 2, Failing: 1, Suspended: 1},
+		},
+		{
+			APIVersion: "source.toolkit.fluxcd.io/v1",
+			Kind:       "HelmChart",
+			Stats:      fluxcdv1.FluxReconcilerStats{Running: 5, Failing: 2},
+		},
+	}
+
+	setComponentsResources(components, reconcilers)
+	g.Expect(components[0].Resources).To(Equal(45))
+	g.Expect(components[0].FailingResources).To(Equal(1))
+	g.Expect(components[1].Resources).To(Equal(1))
+	g.Expect(components[1].FailingResources).To(Equal(0))
+	g.Expect(components[2].Resources).To(Equal(2))
+	g.Expect(components[2].FailingResources).To(Equal(1))
+	g.Expect(components[3].Resources).To(Equal(8))
+	g.Expect(components[3].FailingResources).To(Equal(3))
+
+	RecordReportMetrics(fluxcdv1.FluxReportSpec{ComponentsStatus: components})
+	metricFamilies, err := reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(HaveLen(1))
+	g.Expect(metricFamilies[0].GetName()).To(Equal("flux_component_resources"))
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(8))
+
+	ResetMetrics("FluxComponent")
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(BeEmpty())
+}
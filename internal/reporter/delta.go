@@ -0,0 +1,54 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package reporter
+
+import (
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// maxDeltaEntries is the maximum number of resources
+// listed in each section of the report delta.
+const maxDeltaEntries = 100
+
+// ComputeDelta compares the failing resources of the previous report
+// with the current ones and returns the readiness transitions.
+// The resources are identified by their kind, namespace and name.
+// If there are no transitions, the function returns nil.
+func ComputeDelta(previous, current []string) *fluxcdv1.FluxReportDelta {
+	prevSet := make(map[string]struct{}, len(previous))
+	for _, res := range previous {
+		prevSet[res] = struct{}{}
+	}
+	currSet := make(map[string]struct{}, len(current))
+	for _, res := range current {
+		currSet[res] = struct{}{}
+	}
+
+	delta := &fluxcdv1.FluxReportDelta{}
+	for _, res := range current {
+		if _, ok := prevSet[res]; !ok {
+			delta.NewlyFailing = append(delta.NewlyFailing, res)
+		}
+	}
+	for _, res := range previous {
+		if _, ok := currSet[res]; !ok {
+			delta.Recovered = append(delta.Recovered, res)
+		}
+	}
+
+	if len(delta.NewlyFailing) == 0 && len(delta.Recovered) == 0 {
+		return nil
+	}
+
+	if len(delta.NewlyFailing) > maxDeltaEntries {
+		delta.NewlyFailing = delta.NewlyFailing[:maxDeltaEntries]
+		delta.Truncated = true
+	}
+	if len(delta.Recovered) > maxDeltaEntries {
+		delta.Recovered = delta.Recovered[:maxDeltaEntries]
+		delta.Truncated = true
+	}
+
+	return delta
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package reporter
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestComputeDelta(t *testing.T) {
+	g := NewWithT(t)
+
+	previous := []string{
+		"HelmRelease/apps/podinfo",
+		"Kustomization/flux-system/infra",
+	}
+	current := []string{
+		"GitRepository/flux-system/flux-system",
+		"HelmRelease/apps/podinfo",
+	}
+
+	delta := ComputeDelta(previous, current)
+	g.Expect(delta).ToNot(BeNil())
+	g.Expect(delta.NewlyFailing).To(ConsistOf("GitRepository/flux-system/flux-system"))
+	g.Expect(delta.Recovered).To(ConsistOf("Kustomization/flux-system/infra"))
+	g.Expect(delta.Truncated).To(BeFalse())
+}
+
+func TestComputeDelta_NoChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	failing := []string{"HelmRelease/apps/podinfo"}
+	g.Expect(ComputeDelta(failing, failing)).To(BeNil())
+	g.Expect(ComputeDelta(nil, nil)).To(BeNil())
+}
+
+func TestComputeDelta_Truncated(t *testing.T) {
+	g := NewWithT(t)
+
+	current := make([]string, maxDeltaEntries+10)
+	for i := range current {
+		current[i] = fmt.Sprintf("Kustomization/apps/app-%d", i)
+	}
+
+	delta := ComputeDelta(nil, current)
+	g.Expect(delta.NewlyFailing).To(HaveLen(maxDeltaEntries))
+	g.Expect(delta.Recovered).To(BeEmpty())
+	g.Expect(delta.Truncated).To(BeTrue())
+}
@@ -53,11 +53,16 @@ func (r *FluxStatusReporter) getDistributionStatus(ctx context.Context) fluxcdv1
 		Name:      fmt.Sprintf("%s-entitlement", r.manager),
 	}, entitlementSecret)
 	if err == nil {
-		if _, found := entitlementSecret.Data[entitlement.TokenKey]; found {
+		if status, found := entitlementSecret.Annotations[entitlement.StatusAnnotation]; found {
+			result.Entitlement = status
+		} else if _, found := entitlementSecret.Data[entitlement.TokenKey]; found {
 			result.Entitlement = "Issued"
 			if vendor, found := entitlementSecret.Data[entitlement.VendorKey]; found {
 				result.Entitlement += " by " + string(vendor)
 			}
+			if graceEnd, found := entitlementSecret.Annotations[entitlement.GracePeriodAnnotation]; found {
+				result.Entitlement += ", expired, grace period ends at " + graceEnd
+			}
 		}
 	}
 
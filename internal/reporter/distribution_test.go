@@ -0,0 +1,104 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package reporter
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/entitlement"
+)
+
+// secretClient serves the entitlement secret and
+// returns not found for any other object.
+type secretClient struct {
+	client.Client
+
+	secret *corev1.Secret
+}
+
+func (c *secretClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if s, ok := obj.(*corev1.Secret); ok && c.secret != nil && c.secret.Name == key.Name {
+		c.secret.DeepCopyInto(s)
+		return nil
+	}
+	return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+}
+
+func TestGetDistributionStatus_Entitlement(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		entitlement string
+	}{
+		{
+			name:        "missing secret",
+			entitlement: "Unknown",
+		},
+		{
+			name: "issued",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					entitlement.VendorKey: []byte(entitlement.DefaultVendor),
+					entitlement.TokenKey:  []byte("token"),
+				},
+			},
+			entitlement: "Issued by " + entitlement.DefaultVendor,
+		},
+		{
+			name: "grace period",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						entitlement.GracePeriodAnnotation: "2026-01-01T00:00:00Z",
+					},
+				},
+				Data: map[string][]byte{
+					entitlement.VendorKey: []byte(entitlement.DefaultVendor),
+					entitlement.TokenKey:  []byte("token"),
+				},
+			},
+			entitlement: "Issued by " + entitlement.DefaultVendor + ", expired, grace period ends at 2026-01-01T00:00:00Z",
+		},
+		{
+			name: "service unavailable",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						entitlement.StatusAnnotation: entitlement.UnavailableStatus,
+					},
+				},
+				Data: map[string][]byte{
+					entitlement.VendorKey: []byte(entitlement.DefaultVendor),
+					entitlement.TokenKey:  []byte("token"),
+				},
+			},
+			entitlement: entitlement.UnavailableStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kubeClient := &secretClient{secret: tt.secret}
+			if tt.secret != nil {
+				tt.secret.Name = "flux-operator-entitlement"
+				tt.secret.Namespace = "flux-system"
+			}
+
+			r := NewFluxStatusReporter(kubeClient, "flux", "flux-operator", "flux-system")
+			status := r.getDistributionStatus(context.Background())
+			g.Expect(status.Status).To(Equal("Not Installed"))
+			g.Expect(status.Entitlement).To(Equal(tt.entitlement))
+		})
+	}
+}
@@ -50,6 +50,32 @@ func RecordMetrics(obj unstructured.Unstructured) {
 	}
 }
 
+// RecordReportMetrics records the readiness of the Flux components
+// and the number of total and failing resources reconciled by each
+// component found in the given report.
+func RecordReportMetrics(report fluxcdv1.FluxReportSpec) {
+	metrics["FluxComponentReady"].Reset()
+	metrics["FluxComponent"].Reset()
+	for _, c := range report.ComponentsStatus {
+		ready := 0.0
+		if c.Ready {
+			ready = 1
+		}
+		metrics["FluxComponentReady"].With(prometheus.Labels{
+			"name":  c.Name,
+			"image": c.Image,
+		}).Set(ready)
+		metrics["FluxComponent"].With(prometheus.Labels{
+			"name":   c.Name,
+			"status": "total",
+		}).Set(float64(c.Resources))
+		metrics["FluxComponent"].With(prometheus.Labels{
+			"name":   c.Name,
+			"status": "failing",
+		}).Set(float64(c.FailingResources))
+	}
+}
+
 // ResetMetrics resets the metrics for the given kind.
 func ResetMetrics(kind string) {
 	metrics[kind].Reset()
@@ -83,6 +109,20 @@ var metrics = map[string]*prometheus.GaugeVec{
 			"path",
 		),
 	),
+	"FluxComponent": prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "flux_component_resources",
+			Help: "The number of total and failing resources reconciled by a Flux component.",
+		},
+		[]string{"name", "status"},
+	),
+	"FluxComponentReady": prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "flux_component_ready",
+			Help: "The readiness of a Flux component, 1 if ready and 0 otherwise.",
+		},
+		[]string{"name", "image"},
+	),
 }
 
 func commonLabelsToValues(obj unstructured.Unstructured) prometheus.Labels {
@@ -6,6 +6,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
 )
 
 func TestRecordMetrics_FluxResource(t *testing.T) {
@@ -143,3 +145,59 @@ func TestRecordMetrics_FluxResource(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(metricFamilies).To(BeEmpty())
 }
+
+func TestRecordReportMetrics(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics["FluxComponentReady"], metrics["FluxComponent"])
+
+	report := fluxcdv1.FluxReportSpec{
+		ComponentsStatus: []fluxcdv1.FluxComponentStatus{
+			{
+				Name:             "kustomize-controller",
+				Image:            "ghcr.io/fluxcd/kustomize-controller:v1.4.0",
+				Ready:            true,
+				Resources:        11,
+				FailingResources: 2,
+			},
+			{
+				Name:  "source-controller",
+				Image: "ghcr.io/fluxcd/source-controller:v1.4.1",
+				Ready: false,
+			},
+		},
+	}
+
+	RecordReportMetrics(report)
+	metricFamilies, err := reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(HaveLen(2))
+
+	values := make(map[string]float64)
+	for _, mf := range metricFamilies {
+		for _, m := range mf.Metric {
+			key := mf.GetName()
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "name" || l.GetName() == "status" {
+					key += "/" + l.GetValue()
+				}
+			}
+			values[key] = m.GetGauge().GetValue()
+		}
+	}
+
+	g.Expect(values).To(Equal(map[string]float64{
+		"flux_component_ready/kustomize-controller":             1,
+		"flux_component_ready/source-controller":                0,
+		"flux_component_resources/kustomize-controller/total":   11,
+		"flux_component_resources/kustomize-controller/failing": 2,
+		"flux_component_resources/source-controller/total":      0,
+		"flux_component_resources/source-controller/failing":    0,
+	}))
+
+	ResetMetrics("FluxComponentReady")
+	ResetMetrics("FluxComponent")
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(BeEmpty())
+}
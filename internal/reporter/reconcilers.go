@@ -7,6 +7,8 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
 	"github.com/fluxcd/pkg/apis/meta"
@@ -22,14 +24,59 @@ import (
 
 func (r *FluxStatusReporter) getReconcilersStatus(ctx context.Context, crds []metav1.GroupVersionKind) ([]fluxcdv1.FluxReconcilerStatus, error) {
 	var multiErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
 	ResetMetrics("FluxResource")
 	resStats := make([]fluxcdv1.FluxReconcilerStatus, len(crds))
+	sem := make(chan struct{}, r.workers())
 	for i, gvk := range crds {
-		var total int
-		var suspended int
-		var failing int
-		var totalSize int64
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, gvk metav1.GroupVersionKind) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			stats, err := r.getReconcilerStats(ctx, gvk)
+			if err != nil {
+				mu.Lock()
+				multiErr = kerrors.NewAggregate([]error{multiErr, err})
+				mu.Unlock()
+			}
+
+			resStats[i] = fluxcdv1.FluxReconcilerStatus{
+				APIVersion: gvk.Group + "/" + gvk.Version,
+				Kind:       gvk.Kind,
+				Stats:      stats,
+			}
+		}(i, gvk)
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(resStats, func(i, j fluxcdv1.FluxReconcilerStatus) int {
+		return cmp.Compare(i.APIVersion+i.Kind, j.APIVersion+j.Kind)
+	})
+
+	return resStats, multiErr
+}
+
+// getReconcilerStats lists the resources of the given kind in batches
+// and computes the running, failing and suspended stats.
+func (r *FluxStatusReporter) getReconcilerStats(ctx context.Context, gvk metav1.GroupVersionKind) (fluxcdv1.FluxReconcilerStats, error) {
+	var total int
+	var suspended int
+	var failing int
+	var totalSize int64
 
+	opts := []client.ListOption{client.InNamespace("")}
+	if r.batchSize > 0 {
+		opts = append(opts, client.Limit(r.batchSize))
+	}
+
+	var continueToken string
+	for {
 		list := unstructured.UnstructuredList{
 			Object: map[string]interface{}{
 				"apiVersion": gvk.Group + "/" + gvk.Version,
@@ -37,49 +84,78 @@ func (r *FluxStatusReporter) getReconcilersStatus(ctx context.Context, crds []me
 			},
 		}
 
-		if err := r.List(ctx, &list, client.InNamespace("")); err == nil {
-			total = len(list.Items)
+		if err := r.List(ctx, &list, append(opts, client.Continue(continueToken))...); err != nil {
+			return fluxcdv1.FluxReconcilerStats{}, err
+		}
 
-			for _, item := range list.Items {
+		total += len(list.Items)
+		for _, item := range list.Items {
+			if r.withinWindow(item) {
 				RecordMetrics(item)
+			}
 
-				if s, _, _ := unstructured.NestedBool(item.Object, "spec", "suspend"); s {
-					suspended++
-				}
+			if s, _, _ := unstructured.NestedBool(item.Object, "spec", "suspend"); s {
+				suspended++
+			}
 
-				if obj, err := status.GetObjectWithConditions(item.Object); err == nil {
-					for _, cond := range obj.Status.Conditions {
-						if cond.Type == meta.ReadyCondition && cond.Status == corev1.ConditionFalse {
-							failing++
-						}
+			if obj, err := status.GetObjectWithConditions(item.Object); err == nil {
+				for _, cond := range obj.Status.Conditions {
+					if cond.Type == meta.ReadyCondition && cond.Status == corev1.ConditionFalse {
+						failing++
+						r.recordFailing(item)
 					}
 				}
+			}
 
-				if size, found, _ := unstructured.NestedInt64(item.Object, "status", "artifact", "size"); found {
-					totalSize += size
-				}
+			if size, found, _ := unstructured.NestedInt64(item.Object, "status", "artifact", "size"); found {
+				totalSize += size
 			}
-		} else {
-			multiErr = kerrors.NewAggregate([]error{multiErr, err})
 		}
 
-		resStats[i] = fluxcdv1.FluxReconcilerStatus{
-			APIVersion: gvk.Group + "/" + gvk.Version,
-			Kind:       gvk.Kind,
-			Stats: fluxcdv1.FluxReconcilerStats{
-				Running:   total - suspended,
-				Failing:   failing,
-				Suspended: suspended,
-				TotalSize: formatSize(totalSize),
-			},
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
 		}
 	}
 
-	slices.SortStableFunc(resStats, func(i, j fluxcdv1.FluxReconcilerStatus) int {
-		return cmp.Compare(i.APIVersion+i.Kind, j.APIVersion+j.Kind)
-	})
+	return fluxcdv1.FluxReconcilerStats{
+		Running:   total - suspended,
+		Failing:   failing,
+		Suspended: suspended,
+		TotalSize: formatSize(totalSize),
+	}, nil
+}
 
-	return resStats, multiErr
+// withinWindow returns true if the Ready condition of the given resource
+// transitioned within the configured window. Resources without a Ready
+// condition are always considered within the window.
+func (r *FluxStatusReporter) withinWindow(obj unstructured.Unstructured) bool {
+	if r.since <= 0 {
+		return true
+	}
+
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != meta.ReadyCondition {
+			continue
+		}
+		ts, _ := cond["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return true
+		}
+		return time.Since(t) <= r.since
+	}
+	return true
+}
+
+// recordFailing adds the given resource to the list of failing resources.
+func (r *FluxStatusReporter) recordFailing(obj unstructured.Unstructured) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failing = append(r.failing,
+		fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
 }
 
 func formatSize(b int64) string {
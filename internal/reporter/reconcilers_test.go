@@ -0,0 +1,166 @@
+// Copyright 2024 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingClient serves paginated lists of generated resources
+// and records the maximum number of in-flight list calls.
+type countingClient struct {
+	client.Client
+
+	items    int
+	stale    int
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+	calls    atomic.Int32
+	mu       sync.Mutex
+	maxLimit int64
+}
+
+func (c *countingClient) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		m := c.maxSeen.Load()
+		if n <= m || c.maxSeen.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	c.calls.Add(1)
+	time.Sleep(time.Millisecond)
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	c.mu.Lock()
+	if listOpts.Limit > c.maxLimit {
+		c.maxLimit = listOpts.Limit
+	}
+	c.mu.Unlock()
+
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := c.items
+	if listOpts.Limit > 0 && start+int(listOpts.Limit) < c.items {
+		end = start + int(listOpts.Limit)
+	}
+
+	ul := list.(*unstructured.UnstructuredList)
+	for i := start; i < end; i++ {
+		item := unstructured.Unstructured{}
+		item.SetAPIVersion(ul.GetAPIVersion())
+		item.SetKind(ul.GetKind())
+		item.SetName(fmt.Sprintf("res-%d", i))
+		if i%10 == 0 {
+			_ = unstructured.SetNestedField(item.Object, true, "spec", "suspend")
+		}
+		if c.stale > 0 {
+			transition := time.Now()
+			if i%c.stale == 0 {
+				transition = transition.Add(-2 * time.Hour)
+			}
+			_ = unstructured.SetNestedSlice(item.Object, []interface{}{
+				map[string]interface{}{
+					"type":               "Ready",
+					"status":             "True",
+					"reason":             "Succeeded",
+					"lastTransitionTime": transition.UTC().Format(time.RFC3339),
+				},
+			}, "status", "conditions")
+		}
+		ul.Items = append(ul.Items, item)
+	}
+	if end < c.items {
+		ul.SetContinue(strconv.Itoa(end))
+	}
+
+	return nil
+}
+
+func TestGetReconcilersStatus_BoundedConcurrency(t *testing.T) {
+	g := NewWithT(t)
+
+	crds := make([]metav1.GroupVersionKind, 20)
+	for i := range crds {
+		crds[i] = metav1.GroupVersionKind{
+			Group:   "test.fluxcd.io",
+			Version: "v1",
+			Kind:    fmt.Sprintf("Kind%02d", len(crds)-i),
+		}
+	}
+
+	kubeClient := &countingClient{items: 1000}
+	rep := NewFluxStatusReporter(kubeClient, "flux", "flux-operator", "flux-system").
+		WithConcurrency(3, 100)
+
+	stats, err := rep.getReconcilersStatus(context.Background(), crds)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(kubeClient.maxSeen.Load()).To(BeNumerically("<=", 3))
+	g.Expect(kubeClient.maxLimit).To(BeEquivalentTo(100))
+	g.Expect(kubeClient.calls.Load()).To(BeEquivalentTo(20 * 10))
+
+	g.Expect(stats).To(HaveLen(20))
+	for i, s := range stats {
+		g.Expect(s.Kind).To(Equal(fmt.Sprintf("Kind%02d", i+1)))
+		g.Expect(s.Stats.Running).To(Equal(900))
+		g.Expect(s.Stats.Suspended).To(Equal(100))
+	}
+}
+
+func TestGetReconcilersStatus_Since(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics["FluxResource"])
+
+	crds := []metav1.GroupVersionKind{
+		{Group: "test.fluxcd.io", Version: "v1", Kind: "Kind"},
+	}
+
+	kubeClient := &countingClient{items: 100, stale: 4}
+	rep := NewFluxStatusReporter(kubeClient, "flux", "flux-operator", "flux-system").
+		WithSince(time.Hour)
+
+	stats, err := rep.getReconcilersStatus(context.Background(), crds)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// All resources are counted.
+	g.Expect(stats).To(HaveLen(1))
+	g.Expect(stats[0].Stats.Running).To(Equal(90))
+	g.Expect(stats[0].Stats.Suspended).To(Equal(10))
+
+	// Only the resources within the window are enumerated.
+	metricFamilies, err := reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(HaveLen(1))
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(75))
+
+	// Disabling the filter enumerates all resources.
+	rep = NewFluxStatusReporter(kubeClient, "flux", "flux-operator", "flux-system")
+	_, err = rep.getReconcilersStatus(context.Background(), crds)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(100))
+
+	ResetMetrics("FluxResource")
+}
@@ -7,8 +7,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/fluxcd/pkg/apis/meta"
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -26,6 +29,12 @@ type FluxStatusReporter struct {
 	manager       string
 	namespace     string
 	labelSelector client.MatchingLabels
+	concurrency   int
+	batchSize     int64
+	since         time.Duration
+
+	mu      sync.Mutex
+	failing []string
 }
 
 // NewFluxStatusReporter creates a new FluxStatusReporter
@@ -40,6 +49,41 @@ func NewFluxStatusReporter(kubeClient client.Client, instance, manager, namespac
 	}
 }
 
+// WithConcurrency sets the maximum number of resource kinds that are
+// listed in parallel and the page size used when listing the resources.
+// A zero batch size disables pagination.
+func (r *FluxStatusReporter) WithConcurrency(concurrency int, batchSize int64) *FluxStatusReporter {
+	r.concurrency = concurrency
+	r.batchSize = batchSize
+	return r
+}
+
+// WithSince restricts the per-resource metrics to the resources whose
+// Ready condition transitioned within the given window. The resources
+// outside the window are still counted in the reconcilers statistics.
+// A zero duration disables the filter.
+func (r *FluxStatusReporter) WithSince(since time.Duration) *FluxStatusReporter {
+	r.since = since
+	return r
+}
+
+func (r *FluxStatusReporter) workers() int {
+	if r.concurrency < 1 {
+		return 1
+	}
+	return r.concurrency
+}
+
+// FailingResources returns the sorted list of Flux resources
+// found in a failed Ready state during the last computation.
+func (r *FluxStatusReporter) FailingResources() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failing := slices.Clone(r.failing)
+	slices.Sort(failing)
+	return failing
+}
+
 // Compute generate the status report of the Flux installation.
 func (r *FluxStatusReporter) Compute(ctx context.Context) (fluxcdv1.FluxReportSpec, error) {
 	report := fluxcdv1.FluxReportSpec{}
@@ -62,6 +106,8 @@ func (r *FluxStatusReporter) Compute(ctx context.Context) (fluxcdv1.FluxReportSp
 	}
 	report.ReconcilersStatus = reconcilersStatus
 
+	setComponentsResources(report.ComponentsStatus, reconcilersStatus)
+
 	syncStatus, err := r.getSyncStatus(ctx, crds)
 	if err != nil {
 		return report, fmt.Errorf("failed to compute sync status: %w", err)